@@ -1,8 +1,15 @@
 package utils
 
 import (
+	"cmp"
+	"container/heap"
+	"context"
+	"fmt"
 	"iter"
+	"math/rand/v2"
+	"runtime"
 	"slices"
+	"sync"
 )
 
 // ---- Slice-based API ----
@@ -101,6 +108,1059 @@ func Chunk[T any](slice []T, size int) [][]T {
 	return result
 }
 
+// ChunkOrError splits a slice into chunks of the specified size
+// Unlike Chunk, it returns an error instead of panicking when size <= 0
+func ChunkOrError[T any](slice []T, size int) ([][]T, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("chunk size must be greater than 0, got %d", size)
+	}
+	return Chunk(slice, size), nil
+}
+
+// ChunkBy splits a slice into runs of adjacent elements that share the same key
+// A new chunk starts whenever the key differs from that of the previous element
+func ChunkBy[T any, K comparable](slice []T, keyFn func(T) K) [][]T {
+	result := make([][]T, 0)
+	if len(slice) == 0 {
+		return result
+	}
+
+	start := 0
+	prev := keyFn(slice[0])
+	for i := 1; i < len(slice); i++ {
+		key := keyFn(slice[i])
+		if key != prev {
+			result = append(result, slice[start:i])
+			start, prev = i, key
+		}
+	}
+	return append(result, slice[start:])
+}
+
+// GroupBy groups elements of a slice into a map keyed by the result of keyFn
+// Elements keep their original order within each group
+func GroupBy[T any, K comparable](slice []T, keyFn func(T) K) map[K][]T {
+	result := make(map[K][]T)
+	for _, v := range slice {
+		key := keyFn(v)
+		result[key] = append(result[key], v)
+	}
+	return result
+}
+
+// Partition splits a slice into elements that satisfy the predicate and those that don't
+func Partition[T any](slice []T, predicate func(T) bool) (matched []T, rest []T) {
+	matched = make([]T, 0)
+	rest = make([]T, 0)
+	for _, v := range slice {
+		if predicate(v) {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+	return matched, rest
+}
+
+// Distinct returns a new slice with duplicate elements removed
+// The first occurrence of each value is kept
+func Distinct[T comparable](slice []T) []T {
+	seen := make(map[T]struct{}, len(slice))
+	result := make([]T, 0)
+	for _, v := range slice {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
+
+// DistinctBy returns a new slice keeping only the first element for each key produced by keyFn
+func DistinctBy[T any, K comparable](slice []T, keyFn func(T) K) []T {
+	seen := make(map[K]struct{}, len(slice))
+	result := make([]T, 0)
+	for _, v := range slice {
+		key := keyFn(v)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
+
+// Reverse returns a new slice with the elements in reverse order
+// Unlike slices.Reverse, the input slice is not modified
+func Reverse[T any](slice []T) []T {
+	result := make([]T, len(slice))
+	for i, v := range slice {
+		result[len(slice)-1-i] = v
+	}
+	return result
+}
+
+// Pair holds two values of possibly different types
+type Pair[T, U any] struct {
+	First  T
+	Second U
+}
+
+// Zip combines two slices into a slice of pairs
+// The result has the length of the shorter slice; extra elements of the longer slice are dropped
+func Zip[T, U any](a []T, b []U) []Pair[T, U] {
+	n := min(len(a), len(b))
+	result := make([]Pair[T, U], n)
+	for i := 0; i < n; i++ {
+		result[i] = Pair[T, U]{First: a[i], Second: b[i]}
+	}
+	return result
+}
+
+// Unzip splits a slice of pairs into two slices of their first and second values
+func Unzip[T, U any](pairs []Pair[T, U]) (first []T, second []U) {
+	first = make([]T, len(pairs))
+	second = make([]U, len(pairs))
+	for i, p := range pairs {
+		first[i] = p.First
+		second[i] = p.Second
+	}
+	return first, second
+}
+
+// Take returns a new slice containing the first n elements
+// Returns the whole slice when n exceeds its length and an empty slice when n <= 0
+func Take[T any](slice []T, n int) []T {
+	n = max(0, min(n, len(slice)))
+	result := make([]T, n)
+	copy(result, slice[:n])
+	return result
+}
+
+// Drop returns a new slice containing all but the first n elements
+// Returns an empty slice when n >= len(slice) and the whole slice when n <= 0
+func Drop[T any](slice []T, n int) []T {
+	n = max(0, min(n, len(slice)))
+	result := make([]T, len(slice)-n)
+	copy(result, slice[n:])
+	return result
+}
+
+// TakeWhile returns the leading elements of a slice that satisfy the predicate
+// It stops at the first element for which the predicate returns false
+func TakeWhile[T any](slice []T, predicate func(T) bool) []T {
+	result := make([]T, 0)
+	for _, v := range slice {
+		if !predicate(v) {
+			break
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+// DropWhile skips the leading elements that satisfy the predicate and returns the rest
+// All elements from the first non-matching one onward are kept
+func DropWhile[T any](slice []T, predicate func(T) bool) []T {
+	i := 0
+	for i < len(slice) && predicate(slice[i]) {
+		i++
+	}
+	result := make([]T, len(slice)-i)
+	copy(result, slice[i:])
+	return result
+}
+
+// Flatten concatenates a slice of slices into a single slice
+// Nil inner slices are treated as empty
+func Flatten[T any](slices [][]T) []T {
+	total := 0
+	for _, s := range slices {
+		total += len(s)
+	}
+	result := make([]T, 0, total)
+	for _, s := range slices {
+		result = append(result, s...)
+	}
+	return result
+}
+
+// FlattenN flattens exactly one level of nesting, concatenating the inner slices in order
+// Go generics cannot express arbitrary nesting depth; use FlattenAny for deeper []any trees
+func FlattenN[T any](nested [][]T) []T {
+	return Flatten(nested)
+}
+
+// FlattenAny recursively flattens nested []any values into a single level
+// Only values of type []any are expanded; typed slices such as []int are kept as single elements
+func FlattenAny(slice []any) []any {
+	result := make([]any, 0, len(slice))
+	for _, v := range slice {
+		if nested, ok := v.([]any); ok {
+			result = append(result, FlattenAny(nested)...)
+		} else {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Count returns the number of elements that satisfy the predicate function
+func Count[T any](slice []T, predicate func(T) bool) int {
+	count := 0
+	for _, v := range slice {
+		if predicate(v) {
+			count++
+		}
+	}
+	return count
+}
+
+// CountBy counts how many elements map to each key produced by keyFn
+func CountBy[T any, K comparable](slice []T, keyFn func(T) K) map[K]int {
+	result := make(map[K]int)
+	for _, v := range slice {
+		result[keyFn(v)]++
+	}
+	return result
+}
+
+// FindIndex returns the index of the first element that satisfies the predicate function
+// Returns -1 if no element matches
+func FindIndex[T any](slice []T, predicate func(T) bool) int {
+	return slices.IndexFunc(slice, predicate)
+}
+
+// FindIndexLast returns the index of the last element that satisfies the predicate function
+// Returns -1 if no element matches
+func FindIndexLast[T any](slice []T, predicate func(T) bool) int {
+	for i := len(slice) - 1; i >= 0; i-- {
+		if predicate(slice[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// FindLast returns the last element that satisfies the predicate function
+// Returns the value and a boolean indicating if an element was found
+func FindLast[T any](slice []T, predicate func(T) bool) (T, bool) {
+	for i := len(slice) - 1; i >= 0; i-- {
+		if predicate(slice[i]) {
+			return slice[i], true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// MinBy returns the element with the smallest projected value
+// On ties the first element wins; returns false if the slice is empty
+func MinBy[T any, O cmp.Ordered](slice []T, projection func(T) O) (T, bool) {
+	var result T
+	if len(slice) == 0 {
+		return result, false
+	}
+	result = slice[0]
+	best := projection(result)
+	for _, v := range slice[1:] {
+		if p := projection(v); cmp.Less(p, best) {
+			result, best = v, p
+		}
+	}
+	return result, true
+}
+
+// MaxBy returns the element with the largest projected value
+// On ties the first element wins; returns false if the slice is empty
+func MaxBy[T any, O cmp.Ordered](slice []T, projection func(T) O) (T, bool) {
+	var result T
+	if len(slice) == 0 {
+		return result, false
+	}
+	result = slice[0]
+	best := projection(result)
+	for _, v := range slice[1:] {
+		if p := projection(v); cmp.Less(best, p) {
+			result, best = v, p
+		}
+	}
+	return result, true
+}
+
+// Number is a constraint that matches any integer or floating-point type
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// SumBy returns the sum of the projected values of all elements
+func SumBy[T any, N Number](slice []T, projection func(T) N) N {
+	var sum N
+	for _, v := range slice {
+		sum += projection(v)
+	}
+	return sum
+}
+
+// Average returns the arithmetic mean of the projected values as a float64
+// Returns false if the slice is empty
+func Average[T any, N Number](slice []T, projection func(T) N) (float64, bool) {
+	if len(slice) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, v := range slice {
+		sum += float64(projection(v))
+	}
+	return sum / float64(len(slice)), true
+}
+
+// MapIndexed transforms each element in a slice, passing its index to the provided function
+func MapIndexed[T, U any](slice []T, f func(int, T) U) []U {
+	result := make([]U, len(slice))
+	for i, v := range slice {
+		result[i] = f(i, v)
+	}
+	return result
+}
+
+// FilterIndexed returns elements whose index and value satisfy the predicate function
+func FilterIndexed[T any](slice []T, predicate func(int, T) bool) []T {
+	result := make([]T, 0)
+	for i, v := range slice {
+		if predicate(i, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// ForEachIndexed executes a provided function once for each slice element and its index
+func ForEachIndexed[T any](slice []T, action func(int, T)) {
+	for i, v := range slice {
+		action(i, v)
+	}
+}
+
+// ReduceRight applies a function against an accumulator and each element in the slice, from last to first
+func ReduceRight[T, U any](slice []T, initialValue U, reducer func(acc U, current T) U) U {
+	result := initialValue
+	for i := len(slice) - 1; i >= 0; i-- {
+		result = reducer(result, slice[i])
+	}
+	return result
+}
+
+// Scan returns the intermediate accumulator values of a reduction
+// Each result element is the accumulator after processing the corresponding input element;
+// the initial value is not included
+func Scan[T, U any](slice []T, initialValue U, reducer func(acc U, current T) U) []U {
+	result := make([]U, len(slice))
+	acc := initialValue
+	for i, v := range slice {
+		acc = reducer(acc, v)
+		result[i] = acc
+	}
+	return result
+}
+
+// Window returns all overlapping subslices of the specified size, sliding by one element
+// A size larger than the slice yields an empty result
+func Window[T any](slice []T, size int) [][]T {
+	if size <= 0 {
+		panic("window size must be greater than 0")
+	}
+
+	if size > len(slice) {
+		return [][]T{}
+	}
+	result := make([][]T, 0, len(slice)-size+1)
+	for i := 0; i+size <= len(slice); i++ {
+		result = append(result, slice[i:i+size])
+	}
+	return result
+}
+
+// Associate builds a map from the key/value pairs returned by f for each element
+// Later elements overwrite earlier ones on key collision
+func Associate[T any, K comparable, V any](slice []T, f func(T) (K, V)) map[K]V {
+	result := make(map[K]V, len(slice))
+	for _, v := range slice {
+		key, value := f(v)
+		result[key] = value
+	}
+	return result
+}
+
+// KeyBy builds a map from the key produced by keyFn to each element
+// Later elements overwrite earlier ones on key collision
+func KeyBy[T any, K comparable](slice []T, keyFn func(T) K) map[K]T {
+	result := make(map[K]T, len(slice))
+	for _, v := range slice {
+		result[keyFn(v)] = v
+	}
+	return result
+}
+
+// Reject returns elements from a slice that do not satisfy the predicate function
+func Reject[T any](slice []T, predicate func(T) bool) []T {
+	result := make([]T, 0)
+	for _, v := range slice {
+		if !predicate(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// None tests whether no element satisfies the provided testing function
+func None[T any](slice []T, predicate func(T) bool) bool {
+	return !slices.ContainsFunc(slice, predicate)
+}
+
+// Tap calls the provided function with the slice and returns the same slice
+func Tap[T any](slice []T, action func([]T)) []T {
+	action(slice)
+	return slice
+}
+
+// Compact returns a new slice with all zero-valued elements removed
+func Compact[T comparable](slice []T) []T {
+	var zero T
+	result := make([]T, 0)
+	for _, v := range slice {
+		if v != zero {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Concat concatenates the provided slices in order into a new slice
+// Nil slices are treated as empty
+func Concat[T any](slices ...[]T) []T {
+	return Flatten(slices)
+}
+
+// Repeat returns a slice containing value repeated count times
+// Returns an empty slice when count <= 0
+func Repeat[T any](value T, count int) []T {
+	result := make([]T, max(0, count))
+	for i := range result {
+		result[i] = value
+	}
+	return result
+}
+
+// Range generates integers from start (inclusive) to end (exclusive) by step
+// A negative step produces a descending range; a step pointing away from end yields an empty slice
+func Range(start, end, step int) []int {
+	if step == 0 {
+		panic("range step must not be 0")
+	}
+
+	result := make([]int, 0)
+	if step > 0 {
+		for i := start; i < end; i += step {
+			result = append(result, i)
+			// Compare distances as uint so stepping past end cannot overflow
+			if uint(end)-uint(i) <= uint(step) {
+				break
+			}
+		}
+	} else {
+		for i := start; i > end; i += step {
+			result = append(result, i)
+			if uint(i)-uint(end) <= uint(-step) {
+				break
+			}
+		}
+	}
+	return result
+}
+
+// Intersection returns the distinct elements present in both slices, in the order of a
+func Intersection[T comparable](a, b []T) []T {
+	inB := make(map[T]struct{}, len(b))
+	for _, v := range b {
+		inB[v] = struct{}{}
+	}
+	return Distinct(Filter(a, func(v T) bool {
+		_, ok := inB[v]
+		return ok
+	}))
+}
+
+// Union returns the distinct elements of both slices
+// Unique elements of a come first, followed by elements of b not already seen
+func Union[T comparable](a, b []T) []T {
+	return Distinct(Concat(a, b))
+}
+
+// Difference returns the distinct elements of a that are not present in b, in the order of a
+func Difference[T comparable](a, b []T) []T {
+	inB := make(map[T]struct{}, len(b))
+	for _, v := range b {
+		inB[v] = struct{}{}
+	}
+	return Distinct(Reject(a, func(v T) bool {
+		_, ok := inB[v]
+		return ok
+	}))
+}
+
+// SortBy returns a new slice sorted in ascending order by the projected key
+// The sort is not stable and the input slice is not modified
+func SortBy[T any, O cmp.Ordered](slice []T, keyFn func(T) O) []T {
+	result := make([]T, len(slice))
+	copy(result, slice)
+	slices.SortFunc(result, func(a, b T) int {
+		return cmp.Compare(keyFn(a), keyFn(b))
+	})
+	return result
+}
+
+// SortStableBy returns a new slice stably sorted in ascending order by the projected key
+// Elements with equal keys keep their original relative order
+func SortStableBy[T any, O cmp.Ordered](slice []T, keyFn func(T) O) []T {
+	result := make([]T, len(slice))
+	copy(result, slice)
+	slices.SortStableFunc(result, func(a, b T) int {
+		return cmp.Compare(keyFn(a), keyFn(b))
+	})
+	return result
+}
+
+// Frequencies counts how many times each distinct value appears in a slice
+func Frequencies[T comparable](slice []T) map[T]int {
+	return CountBy(slice, func(v T) T { return v })
+}
+
+// MostFrequent returns up to k distinct values ordered by descending frequency
+// Ties are broken by first appearance in the slice; only the top k are kept, using a bounded heap
+func MostFrequent[T comparable](slice []T, k int) []T {
+	if k <= 0 {
+		return []T{}
+	}
+
+	freq := Frequencies(slice)
+	top := &frequencyHeap[T]{}
+	// Distinct yields values in first-appearance order, which is the tie-break rank
+	for rank, v := range Distinct(slice) {
+		entry := frequencyEntry[T]{value: v, count: freq[v], rank: rank}
+		if top.Len() < k {
+			heap.Push(top, entry)
+		} else if top.less(top.entries[0], entry) {
+			top.entries[0] = entry
+			heap.Fix(top, 0)
+		}
+	}
+
+	result := make([]T, top.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(top).(frequencyEntry[T]).value
+	}
+	return result
+}
+
+type frequencyEntry[T any] struct {
+	value T
+	count int
+	rank  int
+}
+
+// frequencyHeap is a min-heap whose root is the least frequent, latest-appearing entry
+type frequencyHeap[T any] struct {
+	entries []frequencyEntry[T]
+}
+
+// less reports whether a ranks below b: lower count, or equal count and later first appearance
+func (h *frequencyHeap[T]) less(a, b frequencyEntry[T]) bool {
+	if a.count != b.count {
+		return a.count < b.count
+	}
+	return a.rank > b.rank
+}
+
+func (h *frequencyHeap[T]) Len() int           { return len(h.entries) }
+func (h *frequencyHeap[T]) Less(i, j int) bool { return h.less(h.entries[i], h.entries[j]) }
+func (h *frequencyHeap[T]) Swap(i, j int)      { h.entries[i], h.entries[j] = h.entries[j], h.entries[i] }
+func (h *frequencyHeap[T]) Push(x any)         { h.entries = append(h.entries, x.(frequencyEntry[T])) }
+func (h *frequencyHeap[T]) Pop() any {
+	last := h.entries[len(h.entries)-1]
+	h.entries = h.entries[:len(h.entries)-1]
+	return last
+}
+
+// Product returns the product of the projected values of all elements
+// Returns 1 for an empty slice
+func Product[T any, N Number](slice []T, projection func(T) N) N {
+	var product N = 1
+	for _, v := range slice {
+		product *= projection(v)
+	}
+	return product
+}
+
+// MinMax returns both the minimum and maximum elements of a slice in a single pass
+// Returns false if the slice is empty
+func MinMax[T cmp.Ordered](slice []T) (min, max T, ok bool) {
+	if len(slice) == 0 {
+		return min, max, false
+	}
+	min, max = slice[0], slice[0]
+	for _, v := range slice[1:] {
+		if cmp.Less(v, min) {
+			min = v
+		}
+		if cmp.Less(max, v) {
+			max = v
+		}
+	}
+	return min, max, true
+}
+
+// Pairwise returns each element paired with its successor
+// A slice of length n yields n-1 pairs; slices shorter than two yield an empty result
+func Pairwise[T any](slice []T) []Pair[T, T] {
+	if len(slice) < 2 {
+		return []Pair[T, T]{}
+	}
+	return Zip(slice[:len(slice)-1], slice[1:])
+}
+
+// Transpose turns an m-by-n matrix into an n-by-m matrix
+// Panics if the rows do not all have the same length
+func Transpose[T any](matrix [][]T) [][]T {
+	if len(matrix) == 0 {
+		return [][]T{}
+	}
+
+	cols := len(matrix[0])
+	for _, row := range matrix {
+		if len(row) != cols {
+			panic("transpose requires all rows to have the same length")
+		}
+	}
+
+	result := make([][]T, cols)
+	for j := range result {
+		result[j] = make([]T, len(matrix))
+		for i, row := range matrix {
+			result[j][i] = row[j]
+		}
+	}
+	return result
+}
+
+// FilterMap transforms each element and keeps only the values for which f reports true
+func FilterMap[T, U any](slice []T, f func(T) (U, bool)) []U {
+	result := make([]U, 0)
+	for _, v := range slice {
+		if u, ok := f(v); ok {
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+// FindMap returns the first transformed value for which f reports true
+// Returns the value and a boolean indicating if an element was found
+func FindMap[T, U any](slice []T, f func(T) (U, bool)) (U, bool) {
+	for _, v := range slice {
+		if u, ok := f(v); ok {
+			return u, true
+		}
+	}
+	var zero U
+	return zero, false
+}
+
+// Intersperse returns a new slice with sep inserted between adjacent elements
+func Intersperse[T any](slice []T, sep T) []T {
+	if len(slice) < 2 {
+		result := make([]T, len(slice))
+		copy(result, slice)
+		return result
+	}
+
+	result := make([]T, 0, 2*len(slice)-1)
+	for i, v := range slice {
+		if i > 0 {
+			result = append(result, sep)
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+// ZipWith combines corresponding elements of two slices using f
+// The result has the length of the shorter slice
+func ZipWith[T, U, R any](a []T, b []U, f func(T, U) R) []R {
+	n := min(len(a), len(b))
+	result := make([]R, n)
+	for i := 0; i < n; i++ {
+		result[i] = f(a[i], b[i])
+	}
+	return result
+}
+
+// ZipLongest combines two slices into a slice of pairs with the length of the longer slice
+// Missing elements of the shorter slice are replaced by defaultA or defaultB
+func ZipLongest[T, U any](a []T, b []U, defaultA T, defaultB U) []Pair[T, U] {
+	n := max(len(a), len(b))
+	result := make([]Pair[T, U], n)
+	for i := 0; i < n; i++ {
+		p := Pair[T, U]{First: defaultA, Second: defaultB}
+		if i < len(a) {
+			p.First = a[i]
+		}
+		if i < len(b) {
+			p.Second = b[i]
+		}
+		result[i] = p
+	}
+	return result
+}
+
+// MapErr transforms each element with a function that can fail
+// Stops at the first error and returns a nil slice along with that error
+func MapErr[T, U any](slice []T, f func(T) (U, error)) ([]U, error) {
+	result := make([]U, len(slice))
+	for i, v := range slice {
+		u, err := f(v)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = u
+	}
+	return result, nil
+}
+
+// FilterErr returns elements that satisfy a predicate that can fail
+// Stops at the first error and returns a nil slice along with that error
+func FilterErr[T any](slice []T, predicate func(T) (bool, error)) ([]T, error) {
+	result := make([]T, 0)
+	for _, v := range slice {
+		ok, err := predicate(v)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+// ReduceErr applies a reducer that can fail against an accumulator and each element in the slice
+// Stops at the first error and returns the accumulator reached so far along with that error
+func ReduceErr[T, U any](slice []T, initialValue U, reducer func(acc U, current T) (U, error)) (U, error) {
+	result := initialValue
+	for _, v := range slice {
+		next, err := reducer(result, v)
+		if err != nil {
+			return result, err
+		}
+		result = next
+	}
+	return result, nil
+}
+
+// MapParallel transforms each element using the given number of goroutines
+// Results keep the input order; workers <= 0 defaults to runtime.NumCPU()
+func MapParallel[T, U any](slice []T, workers int, f func(T) U) []U {
+	result := make([]U, len(slice))
+	parallelFor(len(slice), workers, func(i int) {
+		result[i] = f(slice[i])
+	})
+	return result
+}
+
+// ForEachParallel executes a provided function for each element using the given number of goroutines
+// It returns once every call has completed; workers <= 0 defaults to runtime.NumCPU()
+func ForEachParallel[T any](slice []T, workers int, action func(T)) {
+	parallelFor(len(slice), workers, func(i int) {
+		action(slice[i])
+	})
+}
+
+// parallelFor calls fn for every index in [0, n) across a bounded pool of goroutines
+// and returns once all calls have completed
+func parallelFor(n, workers int, fn func(int)) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, n)
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// MapCtx transforms each element with a context-aware function that can fail
+// Cancellation is checked before each element; returns ctx.Err() or the first mapper error
+func MapCtx[T, U any](ctx context.Context, slice []T, f func(context.Context, T) (U, error)) ([]U, error) {
+	result := make([]U, len(slice))
+	for i, v := range slice {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		u, err := f(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = u
+	}
+	return result, nil
+}
+
+// ReduceWhile applies a function against an accumulator and each element until it reports false
+// The accumulator returned alongside false is the final result
+func ReduceWhile[T, U any](slice []T, initialValue U, reducer func(acc U, current T) (U, bool)) U {
+	result := initialValue
+	for _, v := range slice {
+		var more bool
+		result, more = reducer(result, v)
+		if !more {
+			break
+		}
+	}
+	return result
+}
+
+// FirstNonZero returns the first element that is not the zero value
+// Returns the value and a boolean indicating if an element was found
+func FirstNonZero[T comparable](slice []T) (T, bool) {
+	var zero T
+	return Find(slice, func(v T) bool { return v != zero })
+}
+
+// Coalesce returns the first argument that is not the zero value, or the zero value if there is none
+func Coalesce[T comparable](values ...T) T {
+	v, _ := FirstNonZero(values)
+	return v
+}
+
+// DefaultIfEmpty returns fallback when the slice is empty or nil, otherwise the slice itself
+func DefaultIfEmpty[T any](slice []T, fallback []T) []T {
+	if len(slice) == 0 {
+		return fallback
+	}
+	return slice
+}
+
+// Rotate returns a new slice rotated left by n positions
+// A negative n rotates right, and n wraps around the slice length
+func Rotate[T any](slice []T, n int) []T {
+	result := make([]T, len(slice))
+	if len(slice) == 0 {
+		return result
+	}
+
+	n = ((n % len(slice)) + len(slice)) % len(slice)
+	copy(result, slice[n:])
+	copy(result[len(slice)-n:], slice[:n])
+	return result
+}
+
+// SplitAt divides a slice into the elements before index and the elements from index onward
+// The index is clamped to the bounds of the slice; both results are new slices
+func SplitAt[T any](slice []T, index int) (left []T, right []T) {
+	return Take(slice, index), Drop(slice, index)
+}
+
+// Dedup returns a new slice with runs of consecutive equal elements collapsed to one
+func Dedup[T comparable](slice []T) []T {
+	result := make([]T, 0)
+	for i, v := range slice {
+		if i == 0 || v != slice[i-1] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// RunLength holds a value and the number of times it repeats consecutively
+type RunLength[T any] struct {
+	Value T
+	Count int
+}
+
+// RunLengthEncode collapses runs of consecutive equal elements into value/count pairs
+func RunLengthEncode[T comparable](slice []T) []RunLength[T] {
+	result := make([]RunLength[T], 0)
+	for i, v := range slice {
+		if i > 0 && v == slice[i-1] {
+			result[len(result)-1].Count++
+			continue
+		}
+		result = append(result, RunLength[T]{Value: v, Count: 1})
+	}
+	return result
+}
+
+// RunLengthDecode expands value/count pairs back into a slice
+func RunLengthDecode[T any](runs []RunLength[T]) []T {
+	result := make([]T, 0)
+	for _, r := range runs {
+		for i := 0; i < r.Count; i++ {
+			result = append(result, r.Value)
+		}
+	}
+	return result
+}
+
+// Sample returns n distinct elements chosen uniformly at random without replacement
+// When n >= len(slice) all elements are returned in shuffled order; a nil rng uses the package-level math/rand/v2 source
+func Sample[T any](slice []T, n int, rng *rand.Rand) []T {
+	intN := rand.IntN
+	if rng != nil {
+		intN = rng.IntN
+	}
+
+	n = max(0, min(n, len(slice)))
+	result := make([]T, len(slice))
+	copy(result, slice)
+	for i := 0; i < n; i++ {
+		j := i + intN(len(result)-i)
+		result[i], result[j] = result[j], result[i]
+	}
+	return result[:n]
+}
+
+// Shuffle returns a new slice with the elements in random order using a Fisher–Yates shuffle
+// A nil rng uses the package-level math/rand/v2 source; the input slice is not modified
+func Shuffle[T any](slice []T, rng *rand.Rand) []T {
+	return Sample(slice, len(slice), rng)
+}
+
+// InsertAt returns a new slice with values inserted at index
+// Out-of-range indices are clamped to the start or end of the slice
+func InsertAt[T any](slice []T, index int, values ...T) []T {
+	index = max(0, min(index, len(slice)))
+	result := make([]T, 0, len(slice)+len(values))
+	result = append(result, slice[:index]...)
+	result = append(result, values...)
+	return append(result, slice[index:]...)
+}
+
+// RemoveAt returns a new slice with the element at index removed
+// An out-of-range index returns an unchanged copy of the slice
+func RemoveAt[T any](slice []T, index int) []T {
+	if index < 0 || index >= len(slice) {
+		result := make([]T, len(slice))
+		copy(result, slice)
+		return result
+	}
+	result := make([]T, 0, len(slice)-1)
+	result = append(result, slice[:index]...)
+	return append(result, slice[index+1:]...)
+}
+
+// EqualUnordered reports whether two slices contain the same elements with the same counts
+func EqualUnordered[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	freq := Frequencies(a)
+	for _, v := range b {
+		if freq[v] == 0 {
+			return false
+		}
+		freq[v]--
+	}
+	return true
+}
+
+// ContainsAll determines whether a slice includes every one of the target values
+// Returns true when no targets are given
+func ContainsAll[T comparable](slice []T, targets ...T) bool {
+	set := ToSet(slice)
+	for _, t := range targets {
+		if _, ok := set[t]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsAny determines whether a slice includes at least one of the target values
+// Returns false when no targets are given
+func ContainsAny[T comparable](slice []T, targets ...T) bool {
+	set := ToSet(slice)
+	for _, t := range targets {
+		if _, ok := set[t]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Replace returns a new slice with every occurrence of old replaced by new
+func Replace[T comparable](slice []T, old, new T) []T {
+	return Map(slice, func(v T) T {
+		if v == old {
+			return new
+		}
+		return v
+	})
+}
+
+// ReplaceFunc returns a new slice where elements satisfying the predicate are passed through replacement
+func ReplaceFunc[T any](slice []T, predicate func(T) bool, replacement func(T) T) []T {
+	return Map(slice, func(v T) T {
+		if predicate(v) {
+			return replacement(v)
+		}
+		return v
+	})
+}
+
+// Head returns the first element of a slice and whether it exists
+func Head[T any](slice []T) (T, bool) {
+	if len(slice) == 0 {
+		var zero T
+		return zero, false
+	}
+	return slice[0], true
+}
+
+// Tail returns a new slice with every element after the first
+func Tail[T any](slice []T) []T {
+	return Drop(slice, 1)
+}
+
+// At returns the element at index and whether it exists
+// Negative indices count from the end, so -1 is the last element
+func At[T any](slice []T, index int) (T, bool) {
+	if index < 0 {
+		index += len(slice)
+	}
+	if index < 0 || index >= len(slice) {
+		var zero T
+		return zero, false
+	}
+	return slice[index], true
+}
+
 // ---- Iterator-based API (original) ----
 
 // Map transforms each element in a sequence according to the provided function
@@ -143,6 +1203,291 @@ func IterFind[T any](seq iter.Seq[T], predicate func(T) bool) (T, bool) {
 	return result, found
 }
 
+// IterTake returns a sequence that yields at most the first n elements of seq
+func IterTake[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		taken := 0
+		for a := range seq {
+			if !yield(a) {
+				return
+			}
+			taken++
+			if taken >= n {
+				return
+			}
+		}
+	}
+}
+
+// IterDrop returns a sequence that skips the first n elements of seq and yields the rest
+func IterDrop[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		skipped := 0
+		for a := range seq {
+			if skipped < n {
+				skipped++
+				continue
+			}
+			if !yield(a) {
+				return
+			}
+		}
+	}
+}
+
+// IterTakeWhile returns a sequence that yields elements until the predicate first returns false
+func IterTakeWhile[T any](seq iter.Seq[T], predicate func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for a := range seq {
+			if !predicate(a) || !yield(a) {
+				return
+			}
+		}
+	}
+}
+
+// IterDropWhile returns a sequence that skips leading elements while the predicate holds
+// All elements from the first non-matching one onward are yielded
+func IterDropWhile[T any](seq iter.Seq[T], predicate func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		dropping := true
+		for a := range seq {
+			if dropping && predicate(a) {
+				continue
+			}
+			dropping = false
+			if !yield(a) {
+				return
+			}
+		}
+	}
+}
+
+// IterReduce applies a function against an accumulator and each element in the sequence
+func IterReduce[T, U any](seq iter.Seq[T], initialValue U, reducer func(acc U, current T) U) U {
+	result := initialValue
+	for a := range seq {
+		result = reducer(result, a)
+	}
+	return result
+}
+
+// IterForEach executes a provided function once for each sequence element
+func IterForEach[T any](seq iter.Seq[T], action func(T)) {
+	for a := range seq {
+		action(a)
+	}
+}
+
+// IterSome tests whether at least one element in the sequence satisfies the predicate
+// It stops consuming the sequence at the first match
+func IterSome[T any](seq iter.Seq[T], predicate func(T) bool) bool {
+	for a := range seq {
+		if predicate(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// IterEvery tests whether all elements in the sequence satisfy the predicate
+// It stops consuming the sequence at the first failure
+func IterEvery[T any](seq iter.Seq[T], predicate func(T) bool) bool {
+	for a := range seq {
+		if !predicate(a) {
+			return false
+		}
+	}
+	return true
+}
+
+// IterFlatMap maps each element to a sequence and yields the elements of all resulting sequences
+func IterFlatMap[T, U any](seq iter.Seq[T], f func(T) iter.Seq[U]) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for a := range seq {
+			for b := range f(a) {
+				if !yield(b) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// IterChunk returns a sequence of slices holding up to size elements each
+// The final chunk may be shorter; each yielded slice is newly allocated and safe to retain
+func IterChunk[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	if size <= 0 {
+		panic("chunk size must be greater than 0")
+	}
+
+	return func(yield func([]T) bool) {
+		chunk := make([]T, 0, size)
+		for a := range seq {
+			chunk = append(chunk, a)
+			if len(chunk) == size {
+				if !yield(chunk) {
+					return
+				}
+				chunk = make([]T, 0, size)
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// IterZip returns a sequence of pairs pulled from a and b in lockstep
+// It stops as soon as either sequence is exhausted
+func IterZip[T, U any](a iter.Seq[T], b iter.Seq[U]) iter.Seq[Pair[T, U]] {
+	return func(yield func(Pair[T, U]) bool) {
+		nextA, stopA := iter.Pull(a)
+		defer stopA()
+		nextB, stopB := iter.Pull(b)
+		defer stopB()
+
+		for {
+			first, ok := nextA()
+			if !ok {
+				return
+			}
+			second, ok := nextB()
+			if !ok {
+				return
+			}
+			if !yield(Pair[T, U]{First: first, Second: second}) {
+				return
+			}
+		}
+	}
+}
+
+// IterEnumerate returns a sequence that yields each element together with its index
+func IterEnumerate[T any](seq iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for a := range seq {
+			if !yield(i, a) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// IterConcat returns a sequence that yields the elements of each sequence in turn
+func IterConcat[T any](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, seq := range seqs {
+			for a := range seq {
+				if !yield(a) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// IterCount returns the number of elements in the sequence that satisfy the predicate
+// A nil predicate counts every element
+func IterCount[T any](seq iter.Seq[T], predicate func(T) bool) int {
+	count := 0
+	for a := range seq {
+		if predicate == nil || predicate(a) {
+			count++
+		}
+	}
+	return count
+}
+
+// IterDistinct returns a sequence that yields each value only the first time it appears
+func IterDistinct[T comparable](seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		seen := make(map[T]struct{})
+		for a := range seq {
+			if _, ok := seen[a]; ok {
+				continue
+			}
+			seen[a] = struct{}{}
+			if !yield(a) {
+				return
+			}
+		}
+	}
+}
+
+// IterWindow returns a sequence of overlapping windows of the specified size, sliding by one element
+// Each yielded window is a new slice that is safe to retain
+func IterWindow[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	if size <= 0 {
+		panic("window size must be greater than 0")
+	}
+
+	return func(yield func([]T) bool) {
+		buf := make([]T, 0, size)
+		for a := range seq {
+			if len(buf) == size {
+				copy(buf, buf[1:])
+				buf = buf[:size-1]
+			}
+			buf = append(buf, a)
+			if len(buf) == size {
+				window := make([]T, size)
+				copy(window, buf)
+				if !yield(window) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// IterScan returns a sequence of the accumulator values after processing each element
+func IterScan[T, U any](seq iter.Seq[T], initialValue U, reducer func(acc U, current T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		acc := initialValue
+		for a := range seq {
+			acc = reducer(acc, a)
+			if !yield(acc) {
+				return
+			}
+		}
+	}
+}
+
+// IterRepeat returns a sequence that yields value count times
+// A count of -1 repeats forever; any other count <= 0 yields nothing
+func IterRepeat[T any](value T, count int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; count == -1 || i < count; i++ {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// IterCycle returns a sequence that yields the elements of a slice repeatedly forever
+// An empty slice yields nothing
+func IterCycle[T any](slice []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if len(slice) == 0 {
+			return
+		}
+		for {
+			for _, v := range slice {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
 // Helper functions for iter.Seq conversions
 func ToSlice[T any](seq iter.Seq[T]) []T {
 	result := []T{}
@@ -161,3 +1506,118 @@ func FromSlice[T any](slice []T) iter.Seq[T] {
 		}
 	}
 }
+
+// MapToSeq2 returns a sequence of the key/value pairs of a map in arbitrary order
+func MapToSeq2[K comparable, V any](m map[K]V) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range m {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Seq2ToMap collects a key/value sequence into a map
+// Later pairs overwrite earlier ones on duplicate keys
+func Seq2ToMap[K comparable, V any](seq iter.Seq2[K, V]) map[K]V {
+	result := make(map[K]V)
+	for k, v := range seq {
+		result[k] = v
+	}
+	return result
+}
+
+// IterFromChannel returns a sequence that yields values received from ch until it is closed
+func IterFromChannel[T any](ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range ch {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// IterToChannel sends the elements of seq from a new goroutine into the returned channel
+// The channel is closed once seq is exhausted, so the goroutine exits as long as the consumer drains it
+func IterToChannel[T any](seq iter.Seq[T], buffer int) <-chan T {
+	ch := make(chan T, max(0, buffer))
+	go func() {
+		defer close(ch)
+		for a := range seq {
+			ch <- a
+		}
+	}()
+	return ch
+}
+
+// IterToChannelCtx is like IterToChannel but also stops and closes the channel when ctx is cancelled
+// Cancel ctx to abandon the channel early without leaking the goroutine
+func IterToChannelCtx[T any](ctx context.Context, seq iter.Seq[T], buffer int) <-chan T {
+	ch := make(chan T, max(0, buffer))
+	go func() {
+		defer close(ch)
+		for a := range seq {
+			select {
+			case ch <- a:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// ---- Map-based API ----
+
+// Keys returns the keys of a map in arbitrary order
+func Keys[K comparable, V any](m map[K]V) []K {
+	result := make([]K, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
+
+// Values returns the values of a map in arbitrary order
+func Values[K comparable, V any](m map[K]V) []V {
+	result := make([]V, 0, len(m))
+	for _, v := range m {
+		result = append(result, v)
+	}
+	return result
+}
+
+// Entries returns the key/value pairs of a map as a slice of pairs in arbitrary order
+func Entries[K comparable, V any](m map[K]V) []Pair[K, V] {
+	result := make([]Pair[K, V], 0, len(m))
+	for k, v := range m {
+		result = append(result, Pair[K, V]{First: k, Second: v})
+	}
+	return result
+}
+
+// FromEntries builds a map from a slice of key/value pairs
+// Later pairs overwrite earlier ones on key collision
+func FromEntries[K comparable, V any](pairs []Pair[K, V]) map[K]V {
+	result := make(map[K]V, len(pairs))
+	for _, p := range pairs {
+		result[p.First] = p.Second
+	}
+	return result
+}
+
+// ToSet returns a set containing the distinct values of a slice
+func ToSet[T comparable](slice []T) map[T]struct{} {
+	result := make(map[T]struct{}, len(slice))
+	for _, v := range slice {
+		result[v] = struct{}{}
+	}
+	return result
+}
+
+// FromSet returns the values of a set as a slice in arbitrary order
+func FromSet[T comparable](set map[T]struct{}) []T {
+	return Keys(set)
+}
@@ -0,0 +1,2507 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGroupBy(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  map[int][]string
+	}{
+		{"nil input", nil, map[int][]string{}},
+		{"empty input", []string{}, map[int][]string{}},
+		{"duplicate keys keep order", []string{"a", "bb", "c", "dd", "e"}, map[int][]string{1: {"a", "c", "e"}, 2: {"bb", "dd"}}},
+		{"single-element buckets", []string{"a", "bb", "ccc"}, map[int][]string{1: {"a"}, 2: {"bb"}, 3: {"ccc"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GroupBy(tt.input, func(s string) int { return len(s) })
+			if got == nil {
+				t.Fatal("GroupBy returned nil map")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GroupBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupByPointers(t *testing.T) {
+	type user struct {
+		name string
+		team string
+	}
+	a, b, c := &user{"a", "x"}, &user{"b", "y"}, &user{"c", "x"}
+	got := GroupBy([]*user{a, b, c}, func(u *user) string { return u.team })
+	if len(got["x"]) != 2 || got["x"][0] != a || got["x"][1] != c {
+		t.Errorf("GroupBy()[x] = %v, want [%p %p]", got["x"], a, c)
+	}
+	if len(got["y"]) != 1 || got["y"][0] != b {
+		t.Errorf("GroupBy()[y] = %v, want [%p]", got["y"], b)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	isEven := func(n int) bool { return n%2 == 0 }
+	tests := []struct {
+		name        string
+		input       []int
+		predicate   func(int) bool
+		wantMatched []int
+		wantRest    []int
+	}{
+		{"empty", nil, isEven, []int{}, []int{}},
+		{"all true", []int{1, 2, 3}, func(int) bool { return true }, []int{1, 2, 3}, []int{}},
+		{"all false", []int{1, 2, 3}, func(int) bool { return false }, []int{}, []int{1, 2, 3}},
+		{"mixed", []int{1, 2, 3, 4, 5}, isEven, []int{2, 4}, []int{1, 3, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, rest := Partition(tt.input, tt.predicate)
+			if matched == nil || rest == nil {
+				t.Fatal("Partition returned a nil slice")
+			}
+			if !reflect.DeepEqual(matched, tt.wantMatched) || !reflect.DeepEqual(rest, tt.wantRest) {
+				t.Errorf("Partition() = %v, %v, want %v, %v", matched, rest, tt.wantMatched, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []int
+		want  []int
+	}{
+		{"empty", []int{}, []int{}},
+		{"nil", nil, []int{}},
+		{"all duplicates", []int{7, 7, 7}, []int{7}},
+		{"already unique", []int{3, 1, 2}, []int{3, 1, 2}},
+		{"keeps first occurrence", []int{3, 1, 3, 2, 1}, []int{3, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := slices.Clone(tt.input)
+			got := Distinct(tt.input)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Distinct() = %#v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.input, orig) {
+				t.Errorf("Distinct mutated input: %v, want %v", tt.input, orig)
+			}
+		})
+	}
+}
+
+func TestDistinctBy(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	tests := []struct {
+		name  string
+		input []user
+		want  []user
+	}{
+		{"empty", []user{}, []user{}},
+		{"shared key keeps first", []user{{1, "a"}, {2, "b"}, {1, "c"}}, []user{{1, "a"}, {2, "b"}}},
+		{"unique keys", []user{{1, "a"}, {2, "b"}}, []user{{1, "a"}, {2, "b"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DistinctBy(tt.input, func(u user) int { return u.ID })
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DistinctBy() = %#v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReverse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []int
+		want  []int
+	}{
+		{"nil", nil, []int{}},
+		{"single", []int{1}, []int{1}},
+		{"several", []int{1, 2, 3, 4}, []int{4, 3, 2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := slices.Clone(tt.input)
+			got := Reverse(tt.input)
+			if got == nil || len(got) != len(tt.input) || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Reverse() = %#v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.input, orig) {
+				t.Errorf("Reverse mutated input: %v, want %v", tt.input, orig)
+			}
+		})
+	}
+}
+
+func TestZip(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []int
+		want []Pair[string, int]
+	}{
+		{"equal length", []string{"a", "b"}, []int{1, 2}, []Pair[string, int]{{"a", 1}, {"b", 2}}},
+		{"shorter first", []string{"a"}, []int{1, 2, 3}, []Pair[string, int]{{"a", 1}}},
+		{"shorter second", []string{"a", "b", "c"}, []int{1, 2}, []Pair[string, int]{{"a", 1}, {"b", 2}}},
+		{"empty", nil, []int{1}, []Pair[string, int]{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Zip(tt.a, tt.b)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Zip() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnzip(t *testing.T) {
+	first, second := Unzip([]Pair[string, int]{})
+	if first == nil || second == nil || len(first) != 0 || len(second) != 0 {
+		t.Errorf("Unzip(empty) = %#v, %#v, want non-nil empty slices", first, second)
+	}
+
+	tests := []struct {
+		name       string
+		a          []string
+		b          []int
+		wantFirst  []string
+		wantSecond []int
+	}{
+		{"equal length", []string{"a", "b"}, []int{1, 2}, []string{"a", "b"}, []int{1, 2}},
+		{"truncated", []string{"a", "b", "c"}, []int{1}, []string{"a"}, []int{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := Unzip(Zip(tt.a, tt.b))
+			if !reflect.DeepEqual(first, tt.wantFirst) || !reflect.DeepEqual(second, tt.wantSecond) {
+				t.Errorf("Unzip(Zip()) = %v, %v, want %v, %v", first, second, tt.wantFirst, tt.wantSecond)
+			}
+		})
+	}
+}
+
+func TestTake(t *testing.T) {
+	input := []int{1, 2, 3}
+	tests := []struct {
+		name string
+		n    int
+		want []int
+	}{
+		{"n within length", 2, []int{1, 2}},
+		{"n larger than length", 5, []int{1, 2, 3}},
+		{"n zero", 0, []int{}},
+		{"n negative", -1, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Take(input, tt.n)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Take(%d) = %#v, want %v", tt.n, got, tt.want)
+			}
+		})
+	}
+
+	got := Take(input, 2)
+	got[0] = 99
+	if input[0] != 1 {
+		t.Error("Take result aliases the input slice")
+	}
+}
+
+func TestDrop(t *testing.T) {
+	input := []int{1, 2, 3}
+	tests := []struct {
+		name string
+		n    int
+		want []int
+	}{
+		{"n within length", 1, []int{2, 3}},
+		{"n equal to length", 3, []int{}},
+		{"n larger than length", 5, []int{}},
+		{"n zero", 0, []int{1, 2, 3}},
+		{"n negative", -2, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Drop(input, tt.n)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Drop(%d) = %#v, want %v", tt.n, got, tt.want)
+			}
+		})
+	}
+
+	got := Drop(input, 1)
+	got[0] = 99
+	if input[1] != 2 {
+		t.Error("Drop result aliases the input slice")
+	}
+}
+
+func TestTakeWhile(t *testing.T) {
+	lessThan3 := func(n int) bool { return n < 3 }
+	tests := []struct {
+		name      string
+		input     []int
+		predicate func(int) bool
+		want      []int
+	}{
+		{"empty", nil, lessThan3, []int{}},
+		{"never fails", []int{1, 2}, lessThan3, []int{1, 2}},
+		{"fails immediately", []int{5, 1, 2}, lessThan3, []int{}},
+		{"stops at first failure", []int{1, 2, 3, 1}, lessThan3, []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TakeWhile(tt.input, tt.predicate)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TakeWhile() = %#v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDropWhile(t *testing.T) {
+	lessThan3 := func(n int) bool { return n < 3 }
+	tests := []struct {
+		name  string
+		input []int
+		want  []int
+	}{
+		{"empty", nil, []int{}},
+		{"all match", []int{1, 2}, []int{}},
+		{"no match", []int{3, 4}, []int{3, 4}},
+		{"keeps matching elements after first failure", []int{1, 2, 3, 1, 4, 2}, []int{3, 1, 4, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DropWhile(tt.input, lessThan3)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DropWhile() = %#v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		name  string
+		input [][]int
+		want  []int
+	}{
+		{"empty outer", [][]int{}, []int{}},
+		{"nil outer", nil, []int{}},
+		{"nil inner slices skipped", [][]int{nil, {1, 2}, nil, {3}}, []int{1, 2, 3}},
+		{"keeps order", [][]int{{1}, {2, 3}, {4, 5, 6}}, []int{1, 2, 3, 4, 5, 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Flatten(tt.input)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Flatten() = %#v, want %v", got, tt.want)
+			}
+			if cap(got) != len(tt.want) {
+				t.Errorf("Flatten() cap = %d, want %d", cap(got), len(tt.want))
+			}
+		})
+	}
+}
+
+func benchmarkNested() [][]int {
+	nested := make([][]int, 1000)
+	for i := range nested {
+		nested[i] = make([]int, 100)
+	}
+	return nested
+}
+
+func BenchmarkFlatten(b *testing.B) {
+	nested := benchmarkNested()
+	b.ReportAllocs()
+	for b.Loop() {
+		Flatten(nested)
+	}
+}
+
+func BenchmarkFlattenNaiveAppend(b *testing.B) {
+	nested := benchmarkNested()
+	b.ReportAllocs()
+	for b.Loop() {
+		var result []int
+		for _, s := range nested {
+			result = append(result, s...)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	isEven := func(n int) bool { return n%2 == 0 }
+	tests := []struct {
+		name  string
+		input []int
+		want  int
+	}{
+		{"empty", nil, 0},
+		{"zero matches", []int{1, 3, 5}, 0},
+		{"all match", []int{2, 4}, 2},
+		{"mixed", []int{1, 2, 3, 4, 6}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Count(tt.input, isEven); got != tt.want {
+				t.Errorf("Count() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	input := []int{1, 2, 3, 4}
+	if allocs := testing.AllocsPerRun(10, func() { Count(input, isEven) }); allocs != 0 {
+		t.Errorf("Count allocated %v times, want 0", allocs)
+	}
+}
+
+func TestCountBy(t *testing.T) {
+	type order struct {
+		ID     int
+		Status string
+	}
+	tests := []struct {
+		name  string
+		input []order
+		want  map[string]int
+	}{
+		{"empty", nil, map[string]int{}},
+		{"collapsing keys", []order{{1, "paid"}, {2, "open"}, {3, "paid"}, {4, "paid"}}, map[string]int{"paid": 3, "open": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CountBy(tt.input, func(o order) string { return o.Status })
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CountBy() = %v, want %v", got, tt.want)
+			}
+			total := 0
+			for _, n := range got {
+				total += n
+			}
+			if total != len(tt.input) {
+				t.Errorf("CountBy() totals %d, want %d", total, len(tt.input))
+			}
+		})
+	}
+}
+
+func TestFindIndex(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []int
+		want  int
+	}{
+		{"empty", nil, -1},
+		{"not found", []int{1, 3}, -1},
+		{"first of several matches", []int{1, 4, 6, 8}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindIndex(tt.input, func(n int) bool { return n%2 == 0 }); got != tt.want {
+				t.Errorf("FindIndex() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindLast(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []int
+		want      int
+		wantFound bool
+	}{
+		{"empty", nil, 0, false},
+		{"no match", []int{1, 3}, 0, false},
+		{"last of several matches", []int{2, 3, 4, 5, 6, 7}, 6, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := FindLast(tt.input, func(n int) bool { return n%2 == 0 })
+			if got != tt.want || found != tt.wantFound {
+				t.Errorf("FindLast() = %d, %v, want %d, %v", got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
+
+type product struct {
+	Name  string
+	Price int
+}
+
+func TestMinBy(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []product
+		want      product
+		wantFound bool
+	}{
+		{"empty", nil, product{}, false},
+		{"single element", []product{{"a", 5}}, product{"a", 5}, true},
+		{"smallest wins", []product{{"a", 5}, {"b", 2}, {"c", 9}}, product{"b", 2}, true},
+		{"ties keep first", []product{{"a", 3}, {"b", 1}, {"c", 1}}, product{"b", 1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := MinBy(tt.input, func(p product) int { return p.Price })
+			if got != tt.want || found != tt.wantFound {
+				t.Errorf("MinBy() = %v, %v, want %v, %v", got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestMaxBy(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []product
+		want      product
+		wantFound bool
+	}{
+		{"empty", nil, product{}, false},
+		{"largest wins", []product{{"a", 5}, {"b", 2}, {"c", 9}}, product{"c", 9}, true},
+		{"negatives", []product{{"a", -5}, {"b", -2}, {"c", -9}}, product{"b", -2}, true},
+		{"ties keep first", []product{{"a", 1}, {"b", 7}, {"c", 7}}, product{"b", 7}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := MaxBy(tt.input, func(p product) int { return p.Price })
+			if got != tt.want || found != tt.wantFound {
+				t.Errorf("MaxBy() = %v, %v, want %v, %v", got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestSumBy(t *testing.T) {
+	if got := SumBy([]product{{"a", 3}, {"b", 4}}, func(p product) int { return p.Price }); got != 7 {
+		t.Errorf("SumBy(ints) = %d, want 7", got)
+	}
+	if got := SumBy([]float64{0.5, 1.25, 2}, func(f float64) float64 { return f }); got != 3.75 {
+		t.Errorf("SumBy(floats) = %v, want 3.75", got)
+	}
+	if got := SumBy([]product{}, func(p product) int { return p.Price }); got != 0 {
+		t.Errorf("SumBy(empty) = %d, want 0", got)
+	}
+	type cents uint16
+	if got := SumBy([]cents{10, 20}, func(c cents) cents { return c }); got != 30 {
+		t.Errorf("SumBy(named type) = %d, want 30", got)
+	}
+}
+
+func TestAverage(t *testing.T) {
+	identity := func(n int) int { return n }
+	tests := []struct {
+		name   string
+		input  []int
+		want   float64
+		wantOK bool
+	}{
+		{"empty", nil, 0, false},
+		{"would truncate under integer division", []int{1, 2}, 1.5, true},
+		{"exact", []int{2, 4, 6}, 4, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Average(tt.input, identity)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Average() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestMapIndexed(t *testing.T) {
+	got := MapIndexed(nil, func(i int, s string) string { return s })
+	if got == nil || len(got) != 0 {
+		t.Errorf("MapIndexed(nil) = %#v, want empty non-nil slice", got)
+	}
+
+	var indices []int
+	got = MapIndexed([]string{"a", "b", "c"}, func(i int, s string) string {
+		indices = append(indices, i)
+		return strconv.Itoa(i+1) + ":" + s
+	})
+	if want := []string{"1:a", "2:b", "3:c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MapIndexed() = %v, want %v", got, want)
+	}
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(indices, want) {
+		t.Errorf("MapIndexed indices = %v, want %v", indices, want)
+	}
+}
+
+func TestFilterIndexed(t *testing.T) {
+	input := []int{10, 11, 12, 13, 14}
+	tests := []struct {
+		name      string
+		input     []int
+		predicate func(int, int) bool
+		want      []int
+	}{
+		{"empty", nil, func(int, int) bool { return true }, []int{}},
+		{"by index only", input, func(i, _ int) bool { return i%2 == 0 }, []int{10, 12, 14}},
+		{"by value and index", input, func(i, v int) bool { return i > 0 && v%2 == 1 }, []int{11, 13}},
+		{"none kept", input, func(int, int) bool { return false }, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FilterIndexed(tt.input, tt.predicate)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterIndexed() = %#v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForEachIndexed(t *testing.T) {
+	ForEachIndexed([]string{}, func(int, string) { t.Error("action called for empty slice") })
+
+	input := []string{"a", "b", "c"}
+	var indices []int
+	var values []string
+	ForEachIndexed(input, func(i int, s string) {
+		indices = append(indices, i)
+		values = append(values, s)
+	})
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(indices, want) {
+		t.Errorf("ForEachIndexed indices = %v, want %v", indices, want)
+	}
+	if !reflect.DeepEqual(values, input) {
+		t.Errorf("ForEachIndexed values = %v, want %v", values, input)
+	}
+}
+
+func TestReduceRight(t *testing.T) {
+	concat := func(acc string, s string) string { return acc + s }
+	input := []string{"a", "b", "c"}
+	if got := ReduceRight(input, "", concat); got != "cba" {
+		t.Errorf("ReduceRight() = %q, want %q", got, "cba")
+	}
+	if got := Reduce(input, "", concat); got != "abc" {
+		t.Errorf("Reduce() = %q, want %q", got, "abc")
+	}
+	if got := ReduceRight(nil, "init", concat); got != "init" {
+		t.Errorf("ReduceRight(nil) = %q, want %q", got, "init")
+	}
+}
+
+func TestScan(t *testing.T) {
+	sum := func(acc, n int) int { return acc + n }
+	tests := []struct {
+		name    string
+		input   []int
+		initial int
+		want    []int
+	}{
+		{"empty", nil, 0, []int{}},
+		{"cumulative sum", []int{1, 2, 3}, 0, []int{1, 3, 6}},
+		{"initial value excluded", []int{1, 2, 3}, 10, []int{11, 13, 16}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Scan(tt.input, tt.initial, sum)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func assertPanics(t *testing.T, name string, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s did not panic", name)
+		}
+	}()
+	f()
+}
+
+func TestWindow(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		name string
+		size int
+		want [][]int
+	}{
+		{"size 3 on length 5", 3, [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}},
+		{"size 1", 1, [][]int{{1}, {2}, {3}, {4}, {5}}},
+		{"size equal to length", 5, [][]int{{1, 2, 3, 4, 5}}},
+		{"size larger than length", 6, [][]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Window(input, tt.size)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Window(%d) = %v, want %v", tt.size, got, tt.want)
+			}
+		})
+	}
+
+	assertPanics(t, "Window(0)", func() { Window(input, 0) })
+	assertPanics(t, "Window(-1)", func() { Window(input, -1) })
+}
+
+func TestAssociate(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []product
+		want  map[string]int
+	}{
+		{"empty", nil, map[string]int{}},
+		{"distinct keys", []product{{"a", 1}, {"b", 2}}, map[string]int{"a": 1, "b": 2}},
+		{"last write wins", []product{{"a", 1}, {"b", 2}, {"a", 3}}, map[string]int{"a": 3, "b": 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Associate(tt.input, func(p product) (string, int) { return p.Name, p.Price })
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Associate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyBy(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []product
+		want  map[string]product
+	}{
+		{"empty", nil, map[string]product{}},
+		{"distinct keys", []product{{"a", 1}, {"b", 2}}, map[string]product{"a": {"a", 1}, "b": {"b", 2}}},
+		{"last write wins", []product{{"a", 1}, {"a", 3}}, map[string]product{"a": {"a", 3}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := KeyBy(tt.input, func(p product) string { return p.Name })
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("KeyBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReject(t *testing.T) {
+	isEven := func(n int) bool { return n%2 == 0 }
+	tests := []struct {
+		name  string
+		input []int
+		want  []int
+	}{
+		{"empty", nil, []int{}},
+		{"all rejected", []int{2, 4}, []int{}},
+		{"mixed", []int{1, 2, 3, 4, 5}, []int{1, 3, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Reject(tt.input, isEven)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Reject() = %#v, want %v", got, tt.want)
+			}
+			if n := len(got) + len(Filter(tt.input, isEven)); n != len(tt.input) {
+				t.Errorf("Reject and Filter cover %d elements, want %d", n, len(tt.input))
+			}
+		})
+	}
+}
+
+func TestNone(t *testing.T) {
+	isEven := func(n int) bool { return n%2 == 0 }
+	tests := []struct {
+		name  string
+		input []int
+		want  bool
+	}{
+		{"empty", nil, true},
+		{"no match", []int{1, 3, 5}, true},
+		{"single match", []int{1, 2, 3}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := None(tt.input, isEven); got != tt.want {
+				t.Errorf("None() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTap(t *testing.T) {
+	input := []int{1, 2, 3}
+	calls := 0
+	var seen []int
+	got := Tap(input, func(s []int) {
+		calls++
+		seen = s
+	})
+	if calls != 1 {
+		t.Errorf("Tap called action %d times, want 1", calls)
+	}
+	if &got[0] != &input[0] || len(got) != len(input) {
+		t.Error("Tap did not return the identical slice")
+	}
+	if &seen[0] != &input[0] {
+		t.Error("Tap did not pass the identical slice to the action")
+	}
+}
+
+func TestCompact(t *testing.T) {
+	if got, want := Compact([]string{"", "a", "", "b"}), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Compact(strings) = %v, want %v", got, want)
+	}
+	if got, want := Compact([]int{0, 1, 0, 2, 3}), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Compact(ints) = %v, want %v", got, want)
+	}
+	x := 1
+	if got := Compact([]*int{nil, &x, nil}); len(got) != 1 || got[0] != &x {
+		t.Errorf("Compact(pointers) = %v, want [%p]", got, &x)
+	}
+	if got := Compact([]int{0, 0, 0}); got == nil || len(got) != 0 {
+		t.Errorf("Compact(all zero) = %#v, want empty non-nil slice", got)
+	}
+}
+
+func TestConcat(t *testing.T) {
+	tests := []struct {
+		name  string
+		input [][]int
+		want  []int
+	}{
+		{"no arguments", nil, []int{}},
+		{"single slice", [][]int{{1, 2}}, []int{1, 2}},
+		{"several with nil", [][]int{{1}, nil, {2, 3}, nil}, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Concat(tt.input...)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Concat() = %#v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	a := []int{1, 2}
+	got := Concat(a)
+	got[0] = 99
+	if a[0] != 1 {
+		t.Error("Concat result aliases its input")
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+		want  []string
+	}{
+		{"zero", 0, []string{}},
+		{"negative", -3, []string{}},
+		{"several", 3, []string{"x", "x", "x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Repeat("x", tt.count)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Repeat(%d) = %#v, want %v", tt.count, got, tt.want)
+			}
+		})
+	}
+
+	type point struct{ X int }
+	points := Repeat(point{1}, 2)
+	points[0].X = 5
+	if points[1].X != 1 {
+		t.Error("Repeat elements are not independent")
+	}
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		name             string
+		start, end, step int
+		want             []int
+	}{
+		{"ascending", 0, 5, 1, []int{0, 1, 2, 3, 4}},
+		{"ascending by step", 0, 10, 3, []int{0, 3, 6, 9}},
+		{"descending", 5, 0, -2, []int{5, 3, 1}},
+		{"wrong direction ascending", 0, 5, -1, []int{}},
+		{"wrong direction descending", 5, 0, 1, []int{}},
+		{"start equals end", 3, 3, 1, []int{}},
+		{"overflow near max", math.MaxInt - 1, math.MaxInt, 2, []int{math.MaxInt - 1}},
+		{"overflow near min", math.MinInt + 1, math.MinInt, -2, []int{math.MinInt + 1}},
+		{"full range ascending", math.MinInt, math.MaxInt, math.MaxInt, []int{math.MinInt, -1, math.MaxInt - 1}},
+		{"full range descending", math.MaxInt, math.MinInt, math.MinInt, []int{math.MaxInt, -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Range(tt.start, tt.end, tt.step)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Range(%d, %d, %d) = %#v, want %v", tt.start, tt.end, tt.step, got, tt.want)
+			}
+		})
+	}
+
+	assertPanics(t, "Range step 0", func() { Range(0, 5, 0) })
+}
+
+func TestIntersection(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want []string
+	}{
+		{"keeps order of a", []string{"c", "a", "b"}, []string{"a", "b", "x"}, []string{"a", "b"}},
+		{"removes duplicates", []string{"a", "a", "b", "a"}, []string{"a", "b", "b"}, []string{"a", "b"}},
+		{"disjoint", []string{"a"}, []string{"b"}, []string{}},
+		{"empty", nil, nil, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Intersection(tt.a, tt.b)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Intersection() = %#v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnion(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []int
+		want []int
+	}{
+		{"overlapping", []int{1, 2, 3}, []int{3, 4, 1}, []int{1, 2, 3, 4}},
+		{"disjoint", []int{1, 2}, []int{3, 4}, []int{1, 2, 3, 4}},
+		{"internal duplicates", []int{1, 1, 2}, []int{2, 3, 3}, []int{1, 2, 3}},
+		{"empty", nil, []int{}, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Union(tt.a, tt.b)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Union() = %#v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDifference(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []int
+		want []int
+	}{
+		{"b superset of a", []int{1, 2}, []int{0, 1, 2, 3}, []int{}},
+		{"disjoint", []int{1, 2}, []int{3}, []int{1, 2}},
+		{"duplicates in a", []int{1, 4, 1, 2, 4}, []int{2}, []int{1, 4}},
+		{"empty b", []int{3, 1}, nil, []int{3, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Difference(tt.a, tt.b)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Difference() = %#v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChunkOrError(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5}
+	for _, size := range []int{0, -1} {
+		got, err := ChunkOrError(input, size)
+		if err == nil || got != nil {
+			t.Errorf("ChunkOrError(%d) = %v, %v, want nil and an error", size, got, err)
+		}
+	}
+	for _, size := range []int{1, 2, 5, 10} {
+		got, err := ChunkOrError(input, size)
+		if err != nil {
+			t.Fatalf("ChunkOrError(%d) returned error: %v", size, err)
+		}
+		if want := Chunk(input, size); !reflect.DeepEqual(got, want) {
+			t.Errorf("ChunkOrError(%d) = %v, want %v", size, got, want)
+		}
+	}
+}
+
+func TestChunkBy(t *testing.T) {
+	identity := func(n int) int { return n }
+	tests := []struct {
+		name  string
+		input []int
+		want  [][]int
+	}{
+		{"empty", nil, [][]int{}},
+		{"single element", []int{7}, [][]int{{7}}},
+		{"all same keys", []int{1, 1, 1}, [][]int{{1, 1, 1}}},
+		{"alternating keys", []int{1, 2, 1, 2}, [][]int{{1}, {2}, {1}, {2}}},
+		{"runs", []int{1, 1, 2, 2, 2, 1}, [][]int{{1, 1}, {2, 2, 2}, {1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ChunkBy(tt.input, identity)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChunkBy() = %#v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// countingSeq returns an infinite sequence 0, 1, 2, ... that records how many values were pulled
+func countingSeq(pulled *int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			*pulled++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestIterTake(t *testing.T) {
+	tests := []struct {
+		name       string
+		n          int
+		want       []int
+		wantPulled int
+	}{
+		{"takes first n", 3, []int{0, 1, 2}, 3},
+		{"zero", 0, []int{}, 0},
+		{"negative", -2, []int{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pulled := 0
+			got := ToSlice(IterTake(countingSeq(&pulled), tt.n))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IterTake(%d) = %v, want %v", tt.n, got, tt.want)
+			}
+			if pulled != tt.wantPulled {
+				t.Errorf("IterTake(%d) pulled %d values, want %d", tt.n, pulled, tt.wantPulled)
+			}
+		})
+	}
+
+	if got, want := ToSlice(IterTake(FromSlice([]int{1, 2}), 5)), []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterTake past end = %v, want %v", got, want)
+	}
+}
+
+func TestIterDrop(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		name string
+		n    int
+		want []int
+	}{
+		{"skips first n", 2, []int{3, 4, 5}},
+		{"zero", 0, []int{1, 2, 3, 4, 5}},
+		{"negative", -1, []int{1, 2, 3, 4, 5}},
+		{"n exceeds length", 10, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToSlice(IterDrop(FromSlice(input), tt.n))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IterDrop(%d) = %v, want %v", tt.n, got, tt.want)
+			}
+		})
+	}
+
+	isOdd := func(n int) bool { return n%2 == 1 }
+	double := func(n int) int { return n * 2 }
+	got := ToSlice(IterMap(IterDrop(IterFilter(FromSlice(input), isOdd), 1), double))
+	if want := []int{6, 10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterDrop composed = %v, want %v", got, want)
+	}
+}
+
+func TestIterTakeWhile(t *testing.T) {
+	pulled := 0
+	got := ToSlice(IterTakeWhile(countingSeq(&pulled), func(n int) bool { return n < 3 }))
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterTakeWhile() = %v, want %v", got, want)
+	}
+	if pulled != 4 {
+		t.Errorf("IterTakeWhile pulled %d values, want 4", pulled)
+	}
+
+	got = ToSlice(IterTakeWhile(FromSlice([]int{5, 1}), func(n int) bool { return n < 3 }))
+	if len(got) != 0 {
+		t.Errorf("IterTakeWhile failing first = %v, want empty", got)
+	}
+}
+
+func TestIterDropWhile(t *testing.T) {
+	lessThan3 := func(n int) bool { return n < 3 }
+	tests := []struct {
+		name  string
+		input []int
+		want  []int
+	}{
+		{"empty", nil, []int{}},
+		{"all match", []int{1, 2}, []int{}},
+		{"no match", []int{3, 4}, []int{3, 4}},
+		{"keeps matching elements after first failure", []int{1, 2, 3, 1, 4, 2}, []int{3, 1, 4, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToSlice(IterDropWhile(FromSlice(tt.input), lessThan3))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IterDropWhile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIterReduce(t *testing.T) {
+	concat := func(acc string, n int) string { return acc + strconv.Itoa(n) }
+	for _, input := range [][]int{nil, {1}, {1, 2, 3}} {
+		got := IterReduce(FromSlice(input), ">", concat)
+		if want := Reduce(input, ">", concat); got != want {
+			t.Errorf("IterReduce(%v) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestIterForEach(t *testing.T) {
+	IterForEach(FromSlice([]int{}), func(int) { t.Error("action called for empty sequence") })
+
+	input := []int{3, 1, 2}
+	var seen []int
+	IterForEach(FromSlice(input), func(n int) { seen = append(seen, n) })
+	if !reflect.DeepEqual(seen, input) {
+		t.Errorf("IterForEach saw %v, want %v", seen, input)
+	}
+}
+
+func TestIterSome(t *testing.T) {
+	pulled := 0
+	if !IterSome(countingSeq(&pulled), func(n int) bool { return n == 4 }) {
+		t.Error("IterSome() = false, want true")
+	}
+	if pulled != 5 {
+		t.Errorf("IterSome pulled %d values, want 5", pulled)
+	}
+	if IterSome(FromSlice([]int{1, 3}), func(n int) bool { return n%2 == 0 }) {
+		t.Error("IterSome(no match) = true, want false")
+	}
+	if IterSome(FromSlice([]int{}), func(int) bool { return true }) {
+		t.Error("IterSome(empty) = true, want false")
+	}
+}
+
+func TestIterEvery(t *testing.T) {
+	pulled := 0
+	if IterEvery(countingSeq(&pulled), func(n int) bool { return n < 2 }) {
+		t.Error("IterEvery() = true, want false")
+	}
+	if pulled != 3 {
+		t.Errorf("IterEvery pulled %d values, want 3", pulled)
+	}
+	if !IterEvery(FromSlice([]int{2, 4}), func(n int) bool { return n%2 == 0 }) {
+		t.Error("IterEvery(all match) = false, want true")
+	}
+	if !IterEvery(FromSlice([]int{}), func(int) bool { return false }) {
+		t.Error("IterEvery(empty) = false, want true")
+	}
+}
+
+func TestIterFlatMap(t *testing.T) {
+	expand := func(n int) iter.Seq[int] { return FromSlice([]int{n, n * 10}) }
+	got := ToSlice(IterFlatMap(FromSlice([]int{1, 2, 3}), expand))
+	if want := []int{1, 10, 2, 20, 3, 30}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterFlatMap() = %v, want %v", got, want)
+	}
+
+	pulled, calls := 0, 0
+	lazy := IterFlatMap(countingSeq(&pulled), func(n int) iter.Seq[int] {
+		calls++
+		return FromSlice([]int{n, n, n})
+	})
+	if pulled != 0 || calls != 0 {
+		t.Fatalf("IterFlatMap evaluated eagerly: pulled %d, calls %d", pulled, calls)
+	}
+	got = ToSlice(IterTake(lazy, 4))
+	if want := []int{0, 0, 0, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterFlatMap bounded = %v, want %v", got, want)
+	}
+	if pulled != 2 || calls != 2 {
+		t.Errorf("IterFlatMap stopping mid-inner: pulled %d, calls %d, want 2, 2", pulled, calls)
+	}
+}
+
+func TestIterChunk(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []int
+		size  int
+		want  [][]int
+	}{
+		{"empty", nil, 2, nil},
+		{"exact multiple", []int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {3, 4}}},
+		{"shorter final chunk", []int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{"size larger than input", []int{1, 2}, 5, [][]int{{1, 2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]int
+			for chunk := range IterChunk(FromSlice(tt.input), tt.size) {
+				got = append(got, chunk)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IterChunk(%d) = %v, want %v", tt.size, got, tt.want)
+			}
+		})
+	}
+
+	var retained [][]int
+	for chunk := range IterChunk(FromSlice([]int{1, 2, 3, 4}), 2) {
+		retained = append(retained, chunk)
+		chunk[0] = -chunk[0]
+	}
+	if want := [][]int{{-1, 2}, {-3, 4}}; !reflect.DeepEqual(retained, want) {
+		t.Errorf("IterChunk reused buffers: %v, want %v", retained, want)
+	}
+
+	assertPanics(t, "IterChunk(0)", func() { IterChunk(FromSlice([]int{1}), 0) })
+}
+
+// trackedSeq yields the given values and records whether the sequence has returned
+func trackedSeq[T any](values []T, done *bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		defer func() { *done = true }()
+		for _, v := range values {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func TestIterZip(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []int
+		b    []string
+		want []Pair[int, string]
+	}{
+		{"equal length", []int{1, 2}, []string{"a", "b"}, []Pair[int, string]{{1, "a"}, {2, "b"}}},
+		{"shorter first", []int{1}, []string{"a", "b"}, []Pair[int, string]{{1, "a"}}},
+		{"shorter second", []int{1, 2, 3}, []string{"a"}, []Pair[int, string]{{1, "a"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doneA, doneB bool
+			got := ToSlice(IterZip(trackedSeq(tt.a, &doneA), trackedSeq(tt.b, &doneB)))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IterZip() = %v, want %v", got, tt.want)
+			}
+			if !doneA || !doneB {
+				t.Errorf("IterZip left sources running: a done %v, b done %v", doneA, doneB)
+			}
+		})
+	}
+
+	var doneA, doneB bool
+	for range IterZip(trackedSeq([]int{1, 2, 3}, &doneA), trackedSeq([]int{4, 5, 6}, &doneB)) {
+		break
+	}
+	if !doneA || !doneB {
+		t.Errorf("IterZip early break left sources running: a done %v, b done %v", doneA, doneB)
+	}
+}
+
+func TestIterEnumerate(t *testing.T) {
+	var indices []int
+	var values []string
+	for i, v := range IterEnumerate(FromSlice([]string{"a", "b", "c"})) {
+		indices = append(indices, i)
+		values = append(values, v)
+	}
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(indices, want) {
+		t.Errorf("IterEnumerate indices = %v, want %v", indices, want)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("IterEnumerate values = %v, want %v", values, want)
+	}
+
+	pulled := 0
+	for i := range IterEnumerate(countingSeq(&pulled)) {
+		if i == 2 {
+			break
+		}
+	}
+	if pulled != 3 {
+		t.Errorf("IterEnumerate pulled %d values after break, want 3", pulled)
+	}
+}
+
+func TestIterConcat(t *testing.T) {
+	got := ToSlice(IterConcat(FromSlice([]int{1, 2}), FromSlice([]int{}), FromSlice([]int{3}), FromSlice([]int{4, 5})))
+	if want := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterConcat() = %v, want %v", got, want)
+	}
+	if got := ToSlice(IterConcat[int]()); len(got) != 0 {
+		t.Errorf("IterConcat() with no sequences = %v, want empty", got)
+	}
+
+	pulled, laterPulled := 0, 0
+	got = ToSlice(IterTake(IterConcat(countingSeq(&pulled), countingSeq(&laterPulled)), 2))
+	if want := []int{0, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterConcat bounded = %v, want %v", got, want)
+	}
+	if pulled != 2 || laterPulled != 0 {
+		t.Errorf("IterConcat pulled %d from first and %d from second, want 2 and 0", pulled, laterPulled)
+	}
+}
+
+func TestIterCount(t *testing.T) {
+	isEven := func(n int) bool { return n%2 == 0 }
+	tests := []struct {
+		name      string
+		input     []int
+		predicate func(int) bool
+		want      int
+	}{
+		{"empty", nil, isEven, 0},
+		{"matching", []int{1, 2, 3, 4}, isEven, 2},
+		{"non-matching", []int{1, 3}, isEven, 0},
+		{"nil predicate counts all", []int{1, 2, 3}, nil, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IterCount(FromSlice(tt.input), tt.predicate); got != tt.want {
+				t.Errorf("IterCount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapToSeq2RoundTrip(t *testing.T) {
+	for _, m := range []map[string]int{{}, {"a": 1}, {"a": 1, "b": 2, "c": 3}} {
+		if got := Seq2ToMap(MapToSeq2(m)); !reflect.DeepEqual(got, m) {
+			t.Errorf("Seq2ToMap(MapToSeq2(%v)) = %v", m, got)
+		}
+	}
+
+	pulled := 0
+	for range MapToSeq2(map[int]int{1: 1, 2: 2, 3: 3}) {
+		pulled++
+		break
+	}
+	if pulled != 1 {
+		t.Errorf("MapToSeq2 continued after break: %d iterations", pulled)
+	}
+}
+
+func TestSeq2ToMapDuplicateKeys(t *testing.T) {
+	seq := func(yield func(string, int) bool) {
+		_ = yield("a", 1) && yield("b", 2) && yield("a", 3)
+	}
+	if got, want := Seq2ToMap(seq), map[string]int{"a": 3, "b": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Seq2ToMap() = %v, want %v", got, want)
+	}
+}
+
+func TestKeysValues(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      map[string]int
+		wantKeys   []string
+		wantValues []int
+	}{
+		{"empty", map[string]int{}, []string{}, []int{}},
+		{"nil", nil, []string{}, []int{}},
+		{"several", map[string]int{"b": 2, "a": 1, "c": 3}, []string{"a", "b", "c"}, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, values := Keys(tt.input), Values(tt.input)
+			if keys == nil || values == nil {
+				t.Fatal("Keys or Values returned nil")
+			}
+			slices.Sort(keys)
+			slices.Sort(values)
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("Keys() = %v, want %v", keys, tt.wantKeys)
+			}
+			if !reflect.DeepEqual(values, tt.wantValues) {
+				t.Errorf("Values() = %v, want %v", values, tt.wantValues)
+			}
+		})
+	}
+}
+
+func TestEntriesRoundTrip(t *testing.T) {
+	for _, m := range []map[string]int{{}, {"a": 1, "b": 2, "c": 3}} {
+		entries := Entries(m)
+		if len(entries) != len(m) {
+			t.Errorf("Entries(%v) has %d pairs, want %d", m, len(entries), len(m))
+		}
+		if got := FromEntries(entries); !reflect.DeepEqual(got, m) {
+			t.Errorf("FromEntries(Entries(%v)) = %v", m, got)
+		}
+	}
+}
+
+func TestFromEntriesDuplicateKeys(t *testing.T) {
+	pairs := []Pair[string, int]{{"a", 1}, {"b", 2}, {"a", 3}}
+	if got, want := FromEntries(pairs), map[string]int{"a": 3, "b": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FromEntries() = %v, want %v", got, want)
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	input := []product{{"c", 3}, {"a", 1}, {"b", 2}}
+	orig := slices.Clone(input)
+	got := SortBy(input, func(p product) int { return p.Price })
+	if want := []product{{"a", 1}, {"b", 2}, {"c", 3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortBy() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(input, orig) {
+		t.Errorf("SortBy mutated input: %v, want %v", input, orig)
+	}
+	if got := SortBy([]product(nil), func(p product) int { return p.Price }); got == nil || len(got) != 0 {
+		t.Errorf("SortBy(nil) = %#v, want empty non-nil slice", got)
+	}
+}
+
+func TestSortStableBy(t *testing.T) {
+	input := []product{{"a", 2}, {"b", 1}, {"c", 2}, {"d", 1}, {"e", 2}}
+	orig := slices.Clone(input)
+	got := SortStableBy(input, func(p product) int { return p.Price })
+	if want := []product{{"b", 1}, {"d", 1}, {"a", 2}, {"c", 2}, {"e", 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortStableBy() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(input, orig) {
+		t.Errorf("SortStableBy mutated input: %v, want %v", input, orig)
+	}
+}
+
+func TestFrequencies(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  map[string]int
+	}{
+		{"empty", nil, map[string]int{}},
+		{"repeated values", []string{"a", "b", "a", "c", "a", "b"}, map[string]int{"a": 3, "b": 2, "c": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Frequencies(tt.input)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Frequencies() = %v, want %v", got, tt.want)
+			}
+			total := 0
+			for _, n := range got {
+				total += n
+			}
+			if total != len(tt.input) {
+				t.Errorf("Frequencies() totals %d, want %d", total, len(tt.input))
+			}
+		})
+	}
+}
+
+func TestMostFrequent(t *testing.T) {
+	input := []string{"b", "a", "c", "a", "b", "d", "a"}
+	tests := []struct {
+		name string
+		k    int
+		want []string
+	}{
+		{"top one", 1, []string{"a"}},
+		{"ties by first appearance", 3, []string{"a", "b", "c"}},
+		{"k larger than distinct", 10, []string{"a", "b", "c", "d"}},
+		{"k zero", 0, []string{}},
+		{"k negative", -1, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MostFrequent(input, tt.k)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MostFrequent(%d) = %#v, want %v", tt.k, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMostFrequentMatchesFullSort(t *testing.T) {
+	rng := rand.New(rand.NewPCG(5, 6))
+	for trial := 0; trial < 200; trial++ {
+		input := make([]int, rng.IntN(40))
+		for i := range input {
+			input[i] = rng.IntN(8)
+		}
+		freq := Frequencies(input)
+		want := Distinct(input)
+		slices.SortStableFunc(want, func(a, b int) int { return freq[b] - freq[a] })
+		for k := 1; k <= 9; k++ {
+			if got := MostFrequent(input, k); !reflect.DeepEqual(got, want[:min(k, len(want))]) {
+				t.Fatalf("MostFrequent(%v, %d) = %v, want %v", input, k, got, want[:min(k, len(want))])
+			}
+		}
+	}
+}
+
+func TestProduct(t *testing.T) {
+	identity := func(n int) int { return n }
+	tests := []struct {
+		name  string
+		input []int
+		want  int
+	}{
+		{"empty is one", nil, 1},
+		{"several", []int{2, 3, 4}, 24},
+		{"zero factor", []int{2, 0, 4}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Product(tt.input, identity); got != tt.want {
+				t.Errorf("Product() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+	if got := Product([]float64{0.5, 0.5}, func(f float64) float64 { return f }); got != 0.25 {
+		t.Errorf("Product(floats) = %v, want 0.25", got)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	tests := []struct {
+		name             string
+		input            []int
+		wantMin, wantMax int
+		wantOK           bool
+	}{
+		{"empty", nil, 0, 0, false},
+		{"single element", []int{4}, 4, 4, true},
+		{"several", []int{3, -1, 7, 2}, -1, 7, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMin, gotMax, ok := MinMax(tt.input)
+			if gotMin != tt.wantMin || gotMax != tt.wantMax || ok != tt.wantOK {
+				t.Errorf("MinMax() = %d, %d, %v, want %d, %d, %v", gotMin, gotMax, ok, tt.wantMin, tt.wantMax, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPairwise(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []int
+		want  []Pair[int, int]
+	}{
+		{"length 0", nil, []Pair[int, int]{}},
+		{"length 1", []int{1}, []Pair[int, int]{}},
+		{"overlapping pairs", []int{1, 2, 3, 4}, []Pair[int, int]{{1, 2}, {2, 3}, {3, 4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Pairwise(tt.input)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Pairwise() = %#v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	tests := []struct {
+		name  string
+		input [][]int
+		want  [][]int
+	}{
+		{"empty", nil, [][]int{}},
+		{"square", [][]int{{1, 2}, {3, 4}}, [][]int{{1, 3}, {2, 4}}},
+		{"rectangular", [][]int{{1, 2, 3}, {4, 5, 6}}, [][]int{{1, 4}, {2, 5}, {3, 6}}},
+		{"single row", [][]int{{1, 2}}, [][]int{{1}, {2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Transpose(tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Transpose() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	assertPanics(t, "Transpose(ragged)", func() { Transpose([][]int{{1, 2}, {3}}) })
+}
+
+func TestFilterMap(t *testing.T) {
+	parse := func(s string) (int, bool) {
+		n, err := strconv.Atoi(s)
+		return n, err == nil
+	}
+	tests := []struct {
+		name  string
+		input []string
+		want  []int
+	}{
+		{"empty", nil, []int{}},
+		{"drops false flags in order", []string{"3", "x", "1", "", "2"}, []int{3, 1, 2}},
+		{"all dropped", []string{"x", "y"}, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FilterMap(tt.input, parse)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterMap() = %#v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindMap(t *testing.T) {
+	calls := 0
+	parse := func(s string) (int, bool) {
+		calls++
+		n, err := strconv.Atoi(s)
+		return n, err == nil
+	}
+
+	got, found := FindMap([]string{"x", "42", "7"}, parse)
+	if got != 42 || !found {
+		t.Errorf("FindMap() = %d, %v, want 42, true", got, found)
+	}
+	if calls != 2 {
+		t.Errorf("FindMap called f %d times, want 2", calls)
+	}
+
+	got, found = FindMap([]string{"x", "y"}, parse)
+	if got != 0 || found {
+		t.Errorf("FindMap(no match) = %d, %v, want 0, false", got, found)
+	}
+}
+
+func TestIntersperse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  []string
+	}{
+		{"empty", []string{}, []string{}},
+		{"single", []string{"a"}, []string{"a"}},
+		{"several", []string{"a", "b", "c"}, []string{"a", ",", "b", ",", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Intersperse(tt.input, ",")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Intersperse() = %v, want %v", got, tt.want)
+			}
+			if len(got) > 0 && (got[0] == "," || got[len(got)-1] == ",") {
+				t.Errorf("Intersperse() = %v has a leading or trailing separator", got)
+			}
+		})
+	}
+}
+
+func TestZipWith(t *testing.T) {
+	label := func(s string, n int) string { return s + strconv.Itoa(n) }
+	tests := []struct {
+		name string
+		a    []string
+		b    []int
+		want []string
+	}{
+		{"equal length", []string{"a", "b"}, []int{1, 2}, []string{"a1", "b2"}},
+		{"shorter first", []string{"a"}, []int{1, 2}, []string{"a1"}},
+		{"shorter second", []string{"a", "b", "c"}, []int{1}, []string{"a1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ZipWith(tt.a, tt.b, label)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ZipWith() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZipLongest(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []int
+		want []Pair[string, int]
+	}{
+		{"a longer", []string{"a", "b", "c"}, []int{1}, []Pair[string, int]{{"a", 1}, {"b", -1}, {"c", -1}}},
+		{"b longer", []string{"a"}, []int{1, 2}, []Pair[string, int]{{"a", 1}, {"?", 2}}},
+		{"equal length", []string{"a"}, []int{1}, []Pair[string, int]{{"a", 1}}},
+		{"both empty", nil, nil, []Pair[string, int]{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ZipLongest(tt.a, tt.b, "?", -1)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ZipLongest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapErr(t *testing.T) {
+	got, err := MapErr([]string{"1", "2", "3"}, strconv.Atoi)
+	if err != nil || !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("MapErr() = %v, %v, want [1 2 3], nil", got, err)
+	}
+
+	calls := 0
+	got, err = MapErr([]string{"1", "2", "x", "4"}, func(s string) (int, error) {
+		calls++
+		return strconv.Atoi(s)
+	})
+	if err == nil || got != nil {
+		t.Errorf("MapErr() = %v, %v, want nil and an error", got, err)
+	}
+	if numErr, ok := err.(*strconv.NumError); !ok || numErr.Num != "x" {
+		t.Errorf("MapErr() error = %v, want the Atoi error for %q", err, "x")
+	}
+	if calls != 3 {
+		t.Errorf("MapErr called f %d times, want 3", calls)
+	}
+}
+
+func TestFilterErr(t *testing.T) {
+	isEven := func(n int) (bool, error) { return n%2 == 0, nil }
+	got, err := FilterErr([]int{1, 2, 3, 4}, isEven)
+	if err != nil || !reflect.DeepEqual(got, []int{2, 4}) {
+		t.Errorf("FilterErr() = %v, %v, want [2 4], nil", got, err)
+	}
+	got, err = FilterErr([]int{1, 3}, isEven)
+	if err != nil || got == nil || len(got) != 0 {
+		t.Errorf("FilterErr(no match) = %#v, %v, want empty non-nil slice", got, err)
+	}
+
+	errBoom := errors.New("boom")
+	calls := 0
+	got, err = FilterErr([]int{2, 4, 5, 6}, func(n int) (bool, error) {
+		calls++
+		if n == 5 {
+			return false, errBoom
+		}
+		return true, nil
+	})
+	if !errors.Is(err, errBoom) || got != nil {
+		t.Errorf("FilterErr() = %v, %v, want nil, %v", got, err, errBoom)
+	}
+	if calls != 3 {
+		t.Errorf("FilterErr called predicate %d times, want 3", calls)
+	}
+}
+
+func TestReduceErr(t *testing.T) {
+	sum := func(acc, n int) (int, error) { return acc + n, nil }
+	got, err := ReduceErr([]int{1, 2, 3}, 10, sum)
+	if err != nil || got != 16 {
+		t.Errorf("ReduceErr() = %d, %v, want 16, nil", got, err)
+	}
+
+	errNegative := errors.New("negative")
+	got, err = ReduceErr([]int{1, 2, -1, 4}, 0, func(acc, n int) (int, error) {
+		if n < 0 {
+			return 0, errNegative
+		}
+		return acc + n, nil
+	})
+	if !errors.Is(err, errNegative) || got != 3 {
+		t.Errorf("ReduceErr() = %d, %v, want 3, %v", got, err, errNegative)
+	}
+}
+
+func TestMapParallel(t *testing.T) {
+	square := func(n int) int { return n * n }
+	input := Range(0, 1000, 1)
+	tests := []struct {
+		name    string
+		input   []int
+		workers int
+	}{
+		{"several workers", input, 4},
+		{"single worker", input, 1},
+		{"workers zero defaults to NumCPU", input, 0},
+		{"workers negative defaults to NumCPU", input, -3},
+		{"workers more than elements", []int{1, 2, 3}, 16},
+		{"empty", []int{}, 4},
+		{"nil", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MapParallel(tt.input, tt.workers, square)
+			if want := Map(tt.input, square); !reflect.DeepEqual(got, want) {
+				t.Errorf("MapParallel() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestForEachParallel(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		workers int
+	}{
+		{"several workers", 500, 8},
+		{"workers zero defaults to NumCPU", 500, 0},
+		{"workers more than elements", 3, 16},
+		{"empty", 0, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visits := make([]atomic.Int32, tt.n)
+			ForEachParallel(Range(0, tt.n, 1), tt.workers, func(i int) {
+				visits[i].Add(1)
+			})
+			for i := range visits {
+				if got := visits[i].Load(); got != 1 {
+					t.Errorf("element %d processed %d times, want 1", i, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMapCtx(t *testing.T) {
+	double := func(_ context.Context, n int) (int, error) { return n * 2, nil }
+	got, err := MapCtx(context.Background(), []int{1, 2, 3}, double)
+	if err != nil || !reflect.DeepEqual(got, []int{2, 4, 6}) {
+		t.Errorf("MapCtx() = %v, %v, want [2 4 6], nil", got, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	got, err = MapCtx(ctx, []int{1, 2, 3, 4}, func(_ context.Context, n int) (int, error) {
+		calls++
+		if n == 2 {
+			cancel()
+		}
+		return n, nil
+	})
+	if !errors.Is(err, context.Canceled) || got != nil {
+		t.Errorf("MapCtx(cancelled) = %v, %v, want nil, %v", got, err, context.Canceled)
+	}
+	if calls != 2 {
+		t.Errorf("MapCtx called f %d times after cancellation, want 2", calls)
+	}
+
+	errBoom := errors.New("boom")
+	got, err = MapCtx(context.Background(), []int{1, 2}, func(_ context.Context, n int) (int, error) {
+		if n == 2 {
+			return 0, errBoom
+		}
+		return n, nil
+	})
+	if !errors.Is(err, errBoom) || got != nil {
+		t.Errorf("MapCtx(error) = %v, %v, want nil, %v", got, err, errBoom)
+	}
+}
+
+func TestReduceWhile(t *testing.T) {
+	calls := 0
+	got := ReduceWhile([]int{5, 6, 7, 8}, 0, func(acc, n int) (int, bool) {
+		calls++
+		acc += n
+		return acc, acc < 10
+	})
+	if got != 11 || calls != 2 {
+		t.Errorf("ReduceWhile() = %d after %d calls, want 11 after 2", got, calls)
+	}
+
+	got = ReduceWhile([]int{1, 2, 3}, 0, func(acc, n int) (int, bool) { return acc + n, true })
+	if got != 6 {
+		t.Errorf("ReduceWhile(whole slice) = %d, want 6", got)
+	}
+	if got := ReduceWhile(nil, 4, func(acc, n int) (int, bool) { return acc + n, true }); got != 4 {
+		t.Errorf("ReduceWhile(empty) = %d, want 4", got)
+	}
+}
+
+func TestFirstNonZero(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []string
+		want      string
+		wantFound bool
+	}{
+		{"empty", nil, "", false},
+		{"all zeros", []string{"", ""}, "", false},
+		{"zeros then value", []string{"", "", "x", "y"}, "x", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := FirstNonZero(tt.input)
+			if got != tt.want || found != tt.wantFound {
+				t.Errorf("FirstNonZero() = %q, %v, want %q, %v", got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{"no arguments", nil, ""},
+		{"all zero", []string{"", ""}, ""},
+		{"mix", []string{"", "env", "default"}, "env"},
+		{"first set", []string{"user", "env"}, "user"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Coalesce(tt.values...); got != tt.want {
+				t.Errorf("Coalesce() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultIfEmpty(t *testing.T) {
+	fallback := []int{9}
+	tests := []struct {
+		name  string
+		input []int
+		want  []int
+	}{
+		{"nil", nil, fallback},
+		{"empty", []int{}, fallback},
+		{"non-empty", []int{1, 2}, []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DefaultIfEmpty(tt.input, fallback)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DefaultIfEmpty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		name string
+		n    int
+		want []int
+	}{
+		{"left", 2, []int{3, 4, 5, 1, 2}},
+		{"right", -1, []int{5, 1, 2, 3, 4}},
+		{"zero", 0, []int{1, 2, 3, 4, 5}},
+		{"larger than length", 12, []int{3, 4, 5, 1, 2}},
+		{"negative larger than length", -7, []int{4, 5, 1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Rotate(input, tt.n)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rotate(%d) = %v, want %v", tt.n, got, tt.want)
+			}
+		})
+	}
+	if want := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(input, want) {
+		t.Errorf("Rotate mutated input: %v, want %v", input, want)
+	}
+	if got := Rotate([]int{}, 3); got == nil || len(got) != 0 {
+		t.Errorf("Rotate(empty) = %#v, want empty non-nil slice", got)
+	}
+}
+
+func TestSplitAt(t *testing.T) {
+	input := []int{1, 2, 3, 4}
+	tests := []struct {
+		name      string
+		index     int
+		wantLeft  []int
+		wantRight []int
+	}{
+		{"midpoint", 2, []int{1, 2}, []int{3, 4}},
+		{"zero", 0, []int{}, []int{1, 2, 3, 4}},
+		{"negative", -3, []int{}, []int{1, 2, 3, 4}},
+		{"length", 4, []int{1, 2, 3, 4}, []int{}},
+		{"past end", 9, []int{1, 2, 3, 4}, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left, right := SplitAt(input, tt.index)
+			if left == nil || right == nil {
+				t.Fatal("SplitAt returned a nil slice")
+			}
+			if !reflect.DeepEqual(left, tt.wantLeft) || !reflect.DeepEqual(right, tt.wantRight) {
+				t.Errorf("SplitAt(%d) = %v, %v, want %v, %v", tt.index, left, right, tt.wantLeft, tt.wantRight)
+			}
+		})
+	}
+}
+
+func TestFlattenN(t *testing.T) {
+	tests := []struct {
+		name  string
+		input [][][]int
+		want  [][]int
+	}{
+		{"empty", nil, [][]int{}},
+		{"only one level removed", [][][]int{{{1}, {2, 3}}, nil, {{4}}}, [][]int{{1}, {2, 3}, {4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FlattenN(tt.input)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FlattenN() = %#v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlattenAny(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []any
+		want  []any
+	}{
+		{"empty", []any{}, []any{}},
+		{"flat", []any{1, "a"}, []any{1, "a"}},
+		{"three levels", []any{1, []any{2, []any{3, []any{4, 5}}}, 6}, []any{1, 2, 3, 4, 5, 6}},
+		{"empty nested", []any{[]any{}, 1, []any{[]any{}}}, []any{1}},
+		{"typed slices kept as elements", []any{[]int{1, 2}, []any{3}}, []any{[]int{1, 2}, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FlattenAny(tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FlattenAny() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToSet(t *testing.T) {
+	got := ToSet([]string{"a", "b", "a", "c", "b"})
+	if want := map[string]struct{}{"a": {}, "b": {}, "c": {}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ToSet() = %v, want %v", got, want)
+	}
+	if got := ToSet([]int(nil)); got == nil || len(got) != 0 {
+		t.Errorf("ToSet(nil) = %v, want empty non-nil set", got)
+	}
+}
+
+func TestFromSetRoundTrip(t *testing.T) {
+	input := []int{3, 1, 3, 2, 1}
+	got := FromSet(ToSet(input))
+	slices.Sort(got)
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FromSet(ToSet()) = %v, want %v", got, want)
+	}
+}
+
+func TestIterDistinct(t *testing.T) {
+	got := ToSlice(IterDistinct(FromSlice([]int{3, 1, 3, 2, 1, 4})))
+	if want := []int{3, 1, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterDistinct() = %v, want %v", got, want)
+	}
+
+	pulled := 0
+	got = ToSlice(IterTake(IterDistinct(countingSeq(&pulled)), 3))
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterDistinct bounded = %v, want %v", got, want)
+	}
+	if pulled != 3 {
+		t.Errorf("IterDistinct pulled %d values, want 3", pulled)
+	}
+}
+
+func TestIterWindow(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []int
+		size  int
+		want  [][]int
+	}{
+		{"size 3 on length 5", []int{1, 2, 3, 4, 5}, 3, [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}},
+		{"size equal to length", []int{1, 2}, 2, [][]int{{1, 2}}},
+		{"size larger than length", []int{1, 2}, 3, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]int
+			for w := range IterWindow(FromSlice(tt.input), tt.size) {
+				got = append(got, w)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IterWindow(%d) = %v, want %v", tt.size, got, tt.want)
+			}
+		})
+	}
+
+	pulled := 0
+	var got [][]int
+	for w := range IterWindow(countingSeq(&pulled), 2) {
+		got = append(got, w)
+		if len(got) == 3 {
+			break
+		}
+	}
+	if want := [][]int{{0, 1}, {1, 2}, {2, 3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterWindow streaming = %v, want %v", got, want)
+	}
+	if pulled != 4 {
+		t.Errorf("IterWindow pulled %d values, want 4", pulled)
+	}
+
+	assertPanics(t, "IterWindow(0)", func() { IterWindow(FromSlice([]int{1}), 0) })
+}
+
+func TestIterScan(t *testing.T) {
+	sum := func(acc, n int) int { return acc + n }
+	got := ToSlice(IterScan(FromSlice([]int{1, 2, 3}), 0, sum))
+	if want := Scan([]int{1, 2, 3}, 0, sum); !reflect.DeepEqual(got, want) {
+		t.Errorf("IterScan() = %v, want %v", got, want)
+	}
+
+	pulled := 0
+	got = ToSlice(IterTake(IterScan(countingSeq(&pulled), 0, sum), 4))
+	if want := []int{0, 1, 3, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterScan bounded = %v, want %v", got, want)
+	}
+	if pulled != 4 {
+		t.Errorf("IterScan pulled %d values, want 4", pulled)
+	}
+}
+
+func TestIterRepeat(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+		want  []string
+	}{
+		{"finite", 3, []string{"x", "x", "x"}},
+		{"zero", 0, []string{}},
+		{"negative other than -1", -5, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToSlice(IterRepeat("x", tt.count))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IterRepeat(%d) = %v, want %v", tt.count, got, tt.want)
+			}
+		})
+	}
+
+	if got := ToSlice(IterTake(IterRepeat("x", -1), 4)); len(got) != 4 {
+		t.Errorf("IterRepeat(-1) bounded yielded %d values, want 4", len(got))
+	}
+}
+
+func TestIterCycle(t *testing.T) {
+	got := ToSlice(IterTake(IterCycle([]int{1, 2, 3}), 7))
+	if want := []int{1, 2, 3, 1, 2, 3, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterCycle() = %v, want %v", got, want)
+	}
+	if got := ToSlice(IterCycle([]int{})); len(got) != 0 {
+		t.Errorf("IterCycle(empty) = %v, want empty", got)
+	}
+}
+
+func TestIterFromChannel(t *testing.T) {
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+	close(ch)
+	if got, want := ToSlice(IterFromChannel(ch)), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterFromChannel() = %v, want %v", got, want)
+	}
+
+	closed := make(chan int)
+	close(closed)
+	if got := ToSlice(IterFromChannel(closed)); len(got) != 0 {
+		t.Errorf("IterFromChannel(closed) = %v, want empty", got)
+	}
+}
+
+func TestIterToChannel(t *testing.T) {
+	for _, buffer := range []int{0, 2, -1} {
+		ch := IterToChannel(FromSlice([]int{1, 2, 3}), buffer)
+		if got, want := ToSlice(IterFromChannel(ch)), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+			t.Errorf("IterToChannel(buffer %d) round trip = %v, want %v", buffer, got, want)
+		}
+	}
+
+	ch := IterToChannel(FromSlice([]int{}), 0)
+	if _, ok := <-ch; ok {
+		t.Error("IterToChannel(empty) sent a value, want a closed channel")
+	}
+}
+
+func TestIterToChannelCtx(t *testing.T) {
+	ch := IterToChannelCtx(context.Background(), FromSlice([]int{1, 2, 3}), 1)
+	if got, want := ToSlice(IterFromChannel(ch)), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterToChannelCtx() round trip = %v, want %v", got, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pulled := 0
+	ch = IterToChannelCtx(ctx, countingSeq(&pulled), 0)
+	if v := <-ch; v != 0 {
+		t.Fatalf("IterToChannelCtx first value = %d, want 0", v)
+	}
+	cancel()
+	received := 0
+	for range ch {
+		received++
+	}
+	// The channel is closed only after the goroutine stops pulling, so pulled is final here:
+	// the first value, any values drained before cancellation was observed, and at most one dropped value
+	if pulled > received+2 {
+		t.Errorf("IterToChannelCtx pulled %d values after cancel with %d drained, want at most %d", pulled, received, received+2)
+	}
+}
+
+func TestDedup(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []int
+		want  []int
+	}{
+		{"empty", nil, []int{}},
+		{"separated runs", []int{1, 1, 2, 2, 1}, []int{1, 2, 1}},
+		{"no runs", []int{1, 2, 3}, []int{1, 2, 3}},
+		{"single run", []int{4, 4, 4}, []int{4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Dedup(tt.input)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Dedup() = %#v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunLengthEncode(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  []RunLength[string]
+	}{
+		{"empty", nil, []RunLength[string]{}},
+		{"single run", []string{"a", "a"}, []RunLength[string]{{"a", 2}}},
+		{"separated runs of same value", []string{"a", "a", "b", "a", "a", "a"}, []RunLength[string]{{"a", 2}, {"b", 1}, {"a", 3}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunLengthEncode(tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RunLengthEncode() = %v, want %v", got, tt.want)
+			}
+			want := tt.input
+			if want == nil {
+				want = []string{}
+			}
+			if decoded := RunLengthDecode(got); !reflect.DeepEqual(decoded, want) {
+				t.Errorf("RunLengthDecode(RunLengthEncode()) = %v, want %v", decoded, want)
+			}
+		})
+	}
+}
+
+func TestSample(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	orig := slices.Clone(input)
+
+	first := Sample(input, 3, rand.New(rand.NewPCG(1, 2)))
+	second := Sample(input, 3, rand.New(rand.NewPCG(1, 2)))
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Sample with the same seed = %v and %v, want equal", first, second)
+	}
+	if len(first) != 3 || len(Distinct(first)) != 3 || !ContainsAll(input, first...) {
+		t.Errorf("Sample() = %v, want 3 distinct elements of %v", first, input)
+	}
+	if !reflect.DeepEqual(input, orig) {
+		t.Errorf("Sample mutated input: %v, want %v", input, orig)
+	}
+
+	all := Sample(input, 20, nil)
+	if !EqualUnordered(all, input) {
+		t.Errorf("Sample(n > len) = %v, want all of %v", all, input)
+	}
+	if got := Sample(input, 0, nil); got == nil || len(got) != 0 {
+		t.Errorf("Sample(0) = %#v, want empty non-nil slice", got)
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	input := Range(0, 20, 1)
+	orig := slices.Clone(input)
+
+	first := Shuffle(input, rand.New(rand.NewPCG(3, 4)))
+	second := Shuffle(input, rand.New(rand.NewPCG(3, 4)))
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Shuffle with the same seed = %v and %v, want equal", first, second)
+	}
+	if !EqualUnordered(first, input) {
+		t.Errorf("Shuffle() = %v, want a permutation of %v", first, input)
+	}
+	if !reflect.DeepEqual(input, orig) {
+		t.Errorf("Shuffle mutated input: %v, want %v", input, orig)
+	}
+	if got := Shuffle(input, nil); !EqualUnordered(got, input) {
+		t.Errorf("Shuffle(nil rng) = %v, want a permutation of %v", got, input)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { Shuffle(input, nil) }); allocs != 1 {
+		t.Errorf("Shuffle(nil rng) allocated %v times, want 1", allocs)
+	}
+	if got := Shuffle([]int{}, nil); got == nil || len(got) != 0 {
+		t.Errorf("Shuffle(empty) = %#v, want empty non-nil slice", got)
+	}
+}
+
+func TestInsertAt(t *testing.T) {
+	input := []int{1, 2, 3}
+	tests := []struct {
+		name   string
+		index  int
+		values []int
+		want   []int
+	}{
+		{"start", 0, []int{9}, []int{9, 1, 2, 3}},
+		{"middle multiple values", 1, []int{8, 9}, []int{1, 8, 9, 2, 3}},
+		{"end", 3, []int{9}, []int{1, 2, 3, 9}},
+		{"negative clamps to start", -4, []int{9}, []int{9, 1, 2, 3}},
+		{"past end clamps to end", 10, []int{9}, []int{1, 2, 3, 9}},
+		{"no values", 1, nil, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := InsertAt(input, tt.index, tt.values...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InsertAt(%d, %v) = %v, want %v", tt.index, tt.values, got, tt.want)
+			}
+		})
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(input, want) {
+		t.Errorf("InsertAt mutated input: %v, want %v", input, want)
+	}
+}
+
+func TestRemoveAt(t *testing.T) {
+	input := []int{1, 2, 3}
+	tests := []struct {
+		name  string
+		index int
+		want  []int
+	}{
+		{"first", 0, []int{2, 3}},
+		{"last", 2, []int{1, 2}},
+		{"negative", -1, []int{1, 2, 3}},
+		{"past end", 3, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RemoveAt(input, tt.index)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RemoveAt(%d) = %v, want %v", tt.index, got, tt.want)
+			}
+			got[0] = 99
+			if input[0] != 1 {
+				t.Fatal("RemoveAt result aliases the input slice")
+			}
+		})
+	}
+}
+
+func TestEqualUnordered(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []int
+		want bool
+	}{
+		{"both empty", nil, []int{}, true},
+		{"reordered", []int{1, 2, 2, 3}, []int{2, 3, 1, 2}, true},
+		{"different duplicate counts", []int{1, 1, 2}, []int{1, 2, 2}, false},
+		{"different lengths", []int{1, 2}, []int{1, 2, 2}, false},
+		{"different elements", []int{1, 2}, []int{1, 3}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EqualUnordered(tt.a, tt.b); got != tt.want {
+				t.Errorf("EqualUnordered(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainsAllAny(t *testing.T) {
+	input := []string{"a", "b", "c"}
+	tests := []struct {
+		name    string
+		targets []string
+		wantAll bool
+		wantAny bool
+	}{
+		{"no targets", nil, true, false},
+		{"all present", []string{"c", "a"}, true, true},
+		{"partial overlap", []string{"a", "x"}, false, true},
+		{"none present", []string{"x", "y"}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ContainsAll(input, tt.targets...); got != tt.wantAll {
+				t.Errorf("ContainsAll(%v) = %v, want %v", tt.targets, got, tt.wantAll)
+			}
+			if got := ContainsAny(input, tt.targets...); got != tt.wantAny {
+				t.Errorf("ContainsAny(%v) = %v, want %v", tt.targets, got, tt.wantAny)
+			}
+		})
+	}
+}
+
+func TestReplace(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  []string
+	}{
+		{"multiple occurrences", []string{"n/a", "x", "n/a"}, []string{"", "x", ""}},
+		{"absent value", []string{"x", "y"}, []string{"x", "y"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := slices.Clone(tt.input)
+			got := Replace(tt.input, "n/a", "")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Replace() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.input, orig) {
+				t.Errorf("Replace mutated input: %v, want %v", tt.input, orig)
+			}
+		})
+	}
+}
+
+func TestReplaceFunc(t *testing.T) {
+	input := []string{" a ", "b", " c"}
+	orig := slices.Clone(input)
+	got := ReplaceFunc(input, func(s string) bool { return strings.HasPrefix(s, " ") }, strings.TrimSpace)
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReplaceFunc() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(input, orig) {
+		t.Errorf("ReplaceFunc mutated input: %v, want %v", input, orig)
+	}
+
+	untouched := []string{"x ", "y"}
+	got = ReplaceFunc(untouched, func(s string) bool { return strings.HasPrefix(s, " ") }, strings.TrimSpace)
+	if !reflect.DeepEqual(got, untouched) {
+		t.Errorf("ReplaceFunc(no match) = %v, want %v", got, untouched)
+	}
+}
+
+func TestFindIndexLast(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []int
+		want  int
+	}{
+		{"empty", nil, -1},
+		{"not found", []int{1, 3}, -1},
+		{"last of several matches", []int{2, 4, 5, 6, 7}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindIndexLast(tt.input, func(n int) bool { return n%2 == 0 }); got != tt.want {
+				t.Errorf("FindIndexLast() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeadTail(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []int
+		wantHead  int
+		wantFound bool
+		wantTail  []int
+	}{
+		{"empty", nil, 0, false, []int{}},
+		{"single element", []int{7}, 7, true, []int{}},
+		{"multiple elements", []int{1, 2, 3}, 1, true, []int{2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head, found := Head(tt.input)
+			if head != tt.wantHead || found != tt.wantFound {
+				t.Errorf("Head() = %d, %v, want %d, %v", head, found, tt.wantHead, tt.wantFound)
+			}
+			if got := Tail(tt.input); got == nil || !reflect.DeepEqual(got, tt.wantTail) {
+				t.Errorf("Tail() = %#v, want %v", got, tt.wantTail)
+			}
+		})
+	}
+}
+
+func TestAt(t *testing.T) {
+	input := []string{"a", "b", "c"}
+	tests := []struct {
+		name      string
+		index     int
+		want      string
+		wantFound bool
+	}{
+		{"first", 0, "a", true},
+		{"positive", 2, "c", true},
+		{"last via -1", -1, "c", true},
+		{"first via negative length", -3, "a", true},
+		{"past end", 3, "", false},
+		{"before start", -4, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := At(input, tt.index)
+			if got != tt.want || found != tt.wantFound {
+				t.Errorf("At(%d) = %q, %v, want %q, %v", tt.index, got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+	if _, found := At([]string{}, 0); found {
+		t.Error("At(empty, 0) found an element")
+	}
+}